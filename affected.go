@@ -0,0 +1,99 @@
+package norm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Op is an object operation.
+type Op string
+
+const (
+	OpCreate  Op = "create"
+	OpRead    Op = "read"
+	OpUpdate  Op = "update"
+	OpDelete  Op = "delete"
+	OpRestore Op = "restore"
+)
+
+// RowsPolicy is the allowed number of rows affected by a write.
+type RowsPolicy struct {
+	min int64
+	max int64 // negative means unbounded
+}
+
+// Any allows any number of affected rows including zero.
+var Any = RowsPolicy{min: 0, max: -1}
+
+// AtLeast requires n or more affected rows.
+func AtLeast(n int64) RowsPolicy {
+	return RowsPolicy{min: n, max: -1}
+}
+
+// AtMost requires n or less affected rows.
+func AtMost(n int64) RowsPolicy {
+	return RowsPolicy{min: 0, max: n}
+}
+
+// Exactly requires exactly n affected rows.
+func Exactly(n int64) RowsPolicy {
+	return RowsPolicy{min: n, max: n}
+}
+
+// Check returns *AffectedError when n doesn't satisfy the policy.
+func (p RowsPolicy) Check(n int64) error {
+	if n < p.min || (p.max >= 0 && n > p.max) {
+		return &AffectedError{Policy: p, Affected: n}
+	}
+	return nil
+}
+
+// IsAny reports whether the policy allows any number of rows.
+func (p RowsPolicy) IsAny() bool {
+	return p.min <= 0 && p.max < 0
+}
+
+func (p RowsPolicy) String() string {
+	switch {
+	case p.IsAny():
+		return "any"
+	case p.min == p.max:
+		return fmt.Sprintf("exactly %d", p.min)
+	case p.max < 0:
+		return fmt.Sprintf("at least %d", p.min)
+	case p.min <= 0:
+		return fmt.Sprintf("at most %d", p.max)
+	default:
+		return fmt.Sprintf("from %d to %d", p.min, p.max)
+	}
+}
+
+// AffectedError reports number of affected rows violating the policy.
+// It matches ErrNotAffected when no rows were affected.
+type AffectedError struct {
+	Policy   RowsPolicy
+	Affected int64
+}
+
+func (e *AffectedError) Error() string {
+	return fmt.Sprintf("affected %d rows, want %s", e.Affected, e.Policy)
+}
+
+func (e *AffectedError) Is(target error) bool {
+	return target == ErrNotAffected && e.Affected == 0
+}
+
+type rowsAffectedKey struct{}
+
+// WithRowsAffected makes drivers store number of rows affected by writes made with the context into n.
+func WithRowsAffected(ctx context.Context, n *int64) context.Context {
+	return context.WithValue(ctx, rowsAffectedKey{}, n)
+}
+
+// ReportRowsAffected stores n into the variable set by WithRowsAffected.
+// It is called by drivers after the write.
+func ReportRowsAffected(ctx context.Context, n int64) {
+	if p, ok := ctx.Value(rowsAffectedKey{}).(*int64); ok && p != nil {
+		*p = n
+	}
+}
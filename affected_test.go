@@ -0,0 +1,65 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRowsPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       RowsPolicy
+		n       int64
+		wantErr string
+	}{
+		{"any zero", Any, 0, ""},
+		{"any many", Any, 100, ""},
+		{"at least ok", AtLeast(1), 2, ""},
+		{"at least fail", AtLeast(1), 0, "affected 0 rows, want at least 1"},
+		{"exactly ok", Exactly(1), 1, ""},
+		{"exactly fail", Exactly(1), 2, "affected 2 rows, want exactly 1"},
+		{"at most ok", AtMost(3), 0, ""},
+		{"at most fail", AtMost(3), 4, "affected 4 rows, want at most 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.p.Check(tt.n)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check() error = %v", err)
+				}
+				return
+			}
+
+			var affected *AffectedError
+			if !errors.As(err, &affected) || err.Error() != tt.wantErr {
+				t.Fatalf("Check() error = %v, want %q", err, tt.wantErr)
+			}
+			if affected.Affected != tt.n || affected.Policy != tt.p {
+				t.Errorf("Check() error = %+v", affected)
+			}
+		})
+	}
+}
+
+func TestAffectedError_Is(t *testing.T) {
+	if err := AtLeast(1).Check(0); !errors.Is(err, ErrNotAffected) {
+		t.Errorf("errors.Is(%v, ErrNotAffected) = false, want true", err)
+	}
+
+	if err := Exactly(1).Check(2); errors.Is(err, ErrNotAffected) {
+		t.Errorf("errors.Is(%v, ErrNotAffected) = true, want false", err)
+	}
+}
+
+func TestWithRowsAffected(t *testing.T) {
+	ReportRowsAffected(context.Background(), 1)
+
+	var n int64
+	ReportRowsAffected(WithRowsAffected(context.Background(), &n), 3)
+
+	if n != 3 {
+		t.Errorf("rows affected = %d, want 3", n)
+	}
+}
@@ -0,0 +1,243 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type actorKey struct{}
+
+// WithActor sets actor of changes made with the context, e.g. id of the authenticated user.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns actor set by WithActor.
+func ActorFromContext(ctx context.Context) (actor string, ok bool) {
+	actor, ok = ctx.Value(actorKey{}).(string)
+	return actor, ok
+}
+
+// Change is a changed field of the model.
+type Change struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// AuditRecord describes a change of the object.
+type AuditRecord[A any] struct {
+	Actor string
+	Time  time.Time
+	Op    Op
+	Args  A
+	Diff  []Change
+}
+
+// AuditOption configures Audited.
+type AuditOption func(*auditOptions)
+
+type auditOptions struct {
+	tag string
+	now func() time.Time
+}
+
+// WithAuditTag sets struct tag naming fields of the diff, "db" by default.
+func WithAuditTag(tag string) AuditOption {
+	return func(o *auditOptions) {
+		o.tag = tag
+	}
+}
+
+// WithAuditClock sets time source of audit records, time.Now by default.
+func WithAuditClock(now func() time.Time) AuditOption {
+	return func(o *auditOptions) {
+		o.now = now
+	}
+}
+
+// Audited writes an audit record of every create, update and delete of the object.
+//
+// The before-image is read by the object's own Read, so a missing value is the zero one.
+// The record is written after the change with the same context, hence drivers keeping
+// transaction in the context write both in the same transaction.
+// A failed audit write fails the operation, the caller must roll the transaction back.
+type Audited[M, A any] struct {
+	Object[M, A]
+	log  Creator[AuditRecord[A], A]
+	opts auditOptions
+}
+
+func NewAudited[M, A any](o Object[M, A], log Creator[AuditRecord[A], A], opts ...AuditOption) Audited[M, A] {
+	ao := auditOptions{tag: "db", now: time.Now}
+	for _, opt := range opts {
+		opt(&ao)
+	}
+	return Audited[M, A]{Object: o, log: log, opts: ao}
+}
+
+func (a Audited[M, A]) Create(ctx context.Context, args A, value M) error {
+	before, err := a.before(ctx, args)
+	if err != nil {
+		return err
+	}
+	if err := a.Object.Create(ctx, args, value); err != nil {
+		return err
+	}
+	return a.write(ctx, OpCreate, args, before, value)
+}
+
+func (a Audited[M, A]) Update(ctx context.Context, args A, value M) error {
+	before, err := a.before(ctx, args)
+	if err != nil {
+		return err
+	}
+	if err := a.Object.Update(ctx, args, value); err != nil {
+		return err
+	}
+	return a.write(ctx, OpUpdate, args, before, value)
+}
+
+func (a Audited[M, A]) Delete(ctx context.Context, args A) error {
+	before, err := a.before(ctx, args)
+	if err != nil {
+		return err
+	}
+	if err := a.Object.Delete(ctx, args); err != nil {
+		return err
+	}
+	var zero M
+	return a.write(ctx, OpDelete, args, before, zero)
+}
+
+func (a Audited[M, A]) before(ctx context.Context, args A) (value M, err error) {
+	value, err = a.Object.Read(ctx, args)
+	if errors.Is(err, ErrNotFound) {
+		return value, nil
+	}
+	if err != nil {
+		return value, fmt.Errorf("read before-image: %w", err)
+	}
+	return value, nil
+}
+
+func (a Audited[M, A]) write(ctx context.Context, op Op, args A, before, after M) error {
+	actor, _ := ActorFromContext(ctx)
+
+	rec := AuditRecord[A]{
+		Actor: actor,
+		Time:  a.opts.now(),
+		Op:    op,
+		Args:  args,
+		Diff:  DiffTag(a.opts.tag, before, after),
+	}
+
+	if err := a.log.Create(ctx, args, rec); err != nil {
+		return fmt.Errorf("write audit record: %w", err)
+	}
+
+	return nil
+}
+
+// Diff returns changed fields of the models named by db tags.
+func Diff[M any](old, new M) []Change {
+	return DiffTag("db", old, new)
+}
+
+// DiffTag returns changed fields of the models named by the tag.
+// Fields tagged "-" are skipped, untagged fields are named by the field name
+// and embedded structs are flattened. Pointers to structs are compared by the pointed values.
+// A non-struct model is compared as a whole and reported as a field with empty name.
+func DiffTag[M any](tag string, old, new M) []Change {
+	return diffValues(tag, "", reflect.ValueOf(&old).Elem(), reflect.ValueOf(&new).Elem(), nil)
+}
+
+func diffValues(tag, name string, old, new reflect.Value, changes []Change) []Change {
+	t := old.Type()
+
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		switch {
+		case old.IsNil() && new.IsNil():
+			return changes
+		case old.IsNil():
+			old = reflect.Zero(t.Elem())
+			new = new.Elem()
+		case new.IsNil():
+			old = old.Elem()
+			new = reflect.Zero(t.Elem())
+		default:
+			old, new = old.Elem(), new.Elem()
+		}
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t == timeType {
+		if !equal(old, new) {
+			changes = append(changes, Change{Field: name, Old: old.Interface(), New: new.Interface()})
+		}
+		return changes
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		field, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if field == "-" {
+			continue
+		}
+
+		if f.Anonymous && field == "" && f.Type != timeType && (f.Type.Kind() == reflect.Struct || f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct) {
+			changes = diffValues(tag, name, old.Field(i), new.Field(i), changes)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if field == "" {
+			field = f.Name
+		}
+
+		if !equal(old.Field(i), new.Field(i)) {
+			changes = append(changes, Change{Field: field, Old: old.Field(i).Interface(), New: new.Field(i).Interface()})
+		}
+	}
+
+	return changes
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// equal compares values by their Equal method when the type has one, e.g. time.Time,
+// whose location and monotonic clock reading don't change the instant. Otherwise values are deeply equal.
+func equal(old, new reflect.Value) bool {
+	if old.Kind() == reflect.Pointer && hasEqual(old.Type().Elem()) {
+		if old.IsNil() || new.IsNil() {
+			return old.IsNil() == new.IsNil()
+		}
+		old, new = old.Elem(), new.Elem()
+	}
+
+	if hasEqual(old.Type()) {
+		return old.MethodByName("Equal").Call([]reflect.Value{new})[0].Bool()
+	}
+
+	return reflect.DeepEqual(old.Interface(), new.Interface())
+}
+
+// hasEqual reports whether t has method Equal(t) bool.
+func hasEqual(t reflect.Type) bool {
+	m, ok := t.MethodByName("Equal")
+	if !ok || t.Kind() == reflect.Interface {
+		return false
+	}
+	mt := m.Type
+	return mt.NumIn() == 2 && mt.In(1) == t && mt.NumOut() == 1 && mt.Out(0).Kind() == reflect.Bool
+}
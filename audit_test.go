@@ -0,0 +1,191 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type auditBase struct {
+	ID int `db:"id"`
+}
+
+type auditModel struct {
+	auditBase
+	Name    string `db:"name"`
+	Email   string `db:"email,omitempty"`
+	Secret  string `db:"-"`
+	private int
+}
+
+type memObject struct {
+	values map[int]auditModel
+}
+
+func (o *memObject) Create(ctx context.Context, args int, value auditModel) error {
+	o.values[args] = value
+	return nil
+}
+
+func (o *memObject) Read(ctx context.Context, args int) (auditModel, error) {
+	v, ok := o.values[args]
+	if !ok {
+		return v, ErrNotFound
+	}
+	return v, nil
+}
+
+func (o *memObject) Update(ctx context.Context, args int, value auditModel) error {
+	o.values[args] = value
+	return nil
+}
+
+func (o *memObject) Delete(ctx context.Context, args int) error {
+	delete(o.values, args)
+	return nil
+}
+
+type memLog struct {
+	records []AuditRecord[int]
+	err     error
+}
+
+func (l *memLog) Create(ctx context.Context, args int, value AuditRecord[int]) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.records = append(l.records, value)
+	return nil
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  auditModel
+		new  auditModel
+		want []Change
+	}{
+		{
+			name: "equal",
+			old:  auditModel{Name: "a"},
+			new:  auditModel{Name: "a", Secret: "s", private: 1},
+			want: nil,
+		},
+		{
+			name: "changed",
+			old:  auditModel{auditBase: auditBase{ID: 1}, Name: "a"},
+			new:  auditModel{auditBase: auditBase{ID: 2}, Name: "a", Email: "e"},
+			want: []Change{
+				{Field: "id", Old: 1, New: 2},
+				{Field: "email", Old: "", New: "e"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type auditTimes struct {
+	At    time.Time  `db:"at"`
+	Until *time.Time `db:"until"`
+}
+
+func TestDiff_Time(t *testing.T) {
+	utc := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := utc.In(time.FixedZone("UTC+3", 3*60*60))
+	now := time.Now()
+	later := now.Add(time.Second)
+
+	tests := []struct {
+		name string
+		old  auditTimes
+		new  auditTimes
+		want []Change
+	}{
+		{
+			name: "other location",
+			old:  auditTimes{At: utc, Until: &utc},
+			new:  auditTimes{At: local, Until: &local},
+			want: nil,
+		},
+		{
+			name: "monotonic clock",
+			old:  auditTimes{At: now},
+			new:  auditTimes{At: now.Round(0)},
+			want: nil,
+		},
+		{
+			name: "changed",
+			old:  auditTimes{At: now, Until: &now},
+			new:  auditTimes{At: later},
+			want: []Change{
+				{Field: "at", Old: now, New: later},
+				{Field: "until", Old: &now, New: (*time.Time)(nil)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_Scalar(t *testing.T) {
+	want := []Change{{Field: "", Old: 1, New: 2}}
+	if got := Diff(1, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
+
+func TestAudited(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	obj := &memObject{values: map[int]auditModel{}}
+	log := &memLog{}
+	a := NewAudited[auditModel, int](obj, log, WithAuditClock(func() time.Time { return now }))
+
+	ctx := WithActor(context.Background(), "alice")
+
+	if err := a.Create(ctx, 1, auditModel{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Update(ctx, 1, auditModel{Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []AuditRecord[int]{
+		{Actor: "alice", Time: now, Op: OpCreate, Args: 1, Diff: []Change{{Field: "name", Old: "", New: "a"}}},
+		{Actor: "alice", Time: now, Op: OpUpdate, Args: 1, Diff: []Change{{Field: "name", Old: "a", New: "b"}}},
+		{Actor: "alice", Time: now, Op: OpDelete, Args: 1, Diff: []Change{{Field: "name", Old: "b", New: ""}}},
+	}
+
+	if !reflect.DeepEqual(log.records, want) {
+		t.Errorf("records = %v, want %v", log.records, want)
+	}
+}
+
+func TestAudited_LogError(t *testing.T) {
+	errLog := errors.New("log failed")
+
+	obj := &memObject{values: map[int]auditModel{}}
+	a := NewAudited[auditModel, int](obj, &memLog{err: errLog})
+
+	if err := a.Create(context.Background(), 1, auditModel{Name: "a"}); !errors.Is(err, errLog) {
+		t.Errorf("Create() error = %v, want %v", err, errLog)
+	}
+}
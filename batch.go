@@ -0,0 +1,179 @@
+package norm
+
+import (
+	"context"
+	"sync"
+)
+
+// Pipeline sends reads queued by Pipeliner in one round trip.
+type Pipeline interface {
+	// Send runs queued reads and calls their callbacks with results.
+	Send(ctx context.Context)
+}
+
+// Pipeliner is implemented by readers of drivers able to run several queries in one round trip.
+type Pipeliner[M, A any] interface {
+	// NewPipeline makes empty pipeline of the driver.
+	NewPipeline(ctx context.Context) Pipeline
+	// QueueRead queues read into the pipeline, set is called with the read result when the pipeline is sent.
+	// It returns false when the pipeline is made by another driver or for another database.
+	QueueRead(ctx context.Context, p Pipeline, args A, set func(value M, err error)) bool
+}
+
+// Result is a result of the read queued into Batch, it is set by Batch.ReadAll.
+type Result[M any] struct {
+	Value M
+	Err   error
+}
+
+// Batch collects independent reads to run them together.
+// Reads of Pipeliner readers sharing a database are sent in one round trip,
+// the rest reads run concurrently, or one by one in the context of WithSerialReads.
+//
+// The sql driver doesn't pipeline reads: database/sql has no batch API, and multi-statement
+// queries can't have parameters in the extended protocol of Postgres.
+type Batch struct {
+	// MaxConcurrency limits number of concurrent reads and pipelines, zero means no limit.
+	// It's ignored in the context of WithSerialReads, where reads run one by one.
+	MaxConcurrency int
+
+	queue []batchRead
+}
+
+type serialReadsKey struct{}
+
+// WithSerialReads makes Batch run reads of the context one by one, as they share a connection
+// unsafe for concurrent use. Drivers set it for their transactions, e.g. database/sql one.
+func WithSerialReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, serialReadsKey{}, true)
+}
+
+func serialReads(ctx context.Context) bool {
+	serial, _ := ctx.Value(serialReadsKey{}).(bool)
+	return serial
+}
+
+type batchRead interface {
+	pipeline(ctx context.Context, pipelines *[]Pipeline) bool
+	read(ctx context.Context)
+	err() error
+}
+
+// Queue adds the read of r with args to the batch.
+func Queue[M, A any](b *Batch, r Reader[M, A], args A) *Result[M] {
+	res := &Result[M]{}
+	b.queue = append(b.queue, queuedRead[M, A]{r: r, args: args, res: res})
+	return res
+}
+
+// Len returns number of queued reads.
+func (b *Batch) Len() int {
+	return len(b.queue)
+}
+
+// ReadAll runs queued reads and sets their results.
+// It returns the first error in order of queue, the batch is empty afterwards.
+func (b *Batch) ReadAll(ctx context.Context) error {
+	queue := b.queue
+	b.queue = nil
+
+	var (
+		pipelines []Pipeline
+		tasks     []func()
+	)
+
+	for _, q := range queue {
+		if q.pipeline(ctx, &pipelines) {
+			continue
+		}
+
+		q := q
+		tasks = append(tasks, func() { q.read(ctx) })
+	}
+
+	for _, p := range pipelines {
+		p := p
+		tasks = append(tasks, func() { p.Send(ctx) })
+	}
+
+	b.run(ctx, tasks)
+
+	for _, q := range queue {
+		if err := q.err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *Batch) run(ctx context.Context, tasks []func()) {
+	limit := b.MaxConcurrency
+	if serialReads(ctx) {
+		limit = 1
+	}
+	if limit <= 0 || limit > len(tasks) {
+		limit = len(tasks)
+	}
+
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+
+	for _, task := range tasks {
+		task := task
+
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			task()
+		}()
+	}
+
+	wg.Wait()
+}
+
+type queuedRead[M, A any] struct {
+	r    Reader[M, A]
+	args A
+	res  *Result[M]
+}
+
+func (q queuedRead[M, A]) pipeline(ctx context.Context, pipelines *[]Pipeline) bool {
+	p, ok := q.r.(Pipeliner[M, A])
+	if !ok {
+		return false
+	}
+
+	for _, pl := range *pipelines {
+		if p.QueueRead(ctx, pl, q.args, q.set) {
+			return true
+		}
+	}
+
+	pl := p.NewPipeline(ctx)
+	if !p.QueueRead(ctx, pl, q.args, q.set) {
+		return false
+	}
+
+	*pipelines = append(*pipelines, pl)
+
+	return true
+}
+
+func (q queuedRead[M, A]) set(value M, err error) {
+	q.res.Value, q.res.Err = value, err
+}
+
+func (q queuedRead[M, A]) read(ctx context.Context) {
+	q.set(q.r.Read(ctx, q.args))
+}
+
+func (q queuedRead[M, A]) err() error {
+	return q.res.Err
+}
@@ -0,0 +1,166 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type readerFunc[M, A any] func(ctx context.Context, args A) (M, error)
+
+func (f readerFunc[M, A]) Read(ctx context.Context, args A) (M, error) {
+	return f(ctx, args)
+}
+
+type fakePipeline struct {
+	db    string
+	reads []func()
+	sent  *int32
+}
+
+func (p *fakePipeline) Send(ctx context.Context) {
+	atomic.AddInt32(p.sent, 1)
+	for _, read := range p.reads {
+		read()
+	}
+}
+
+type fakePipeliner struct {
+	db   string
+	sent *int32
+}
+
+func (f fakePipeliner) Read(ctx context.Context, args int) (int, error) {
+	return 0, errors.New("must be pipelined")
+}
+
+func (f fakePipeliner) NewPipeline(ctx context.Context) Pipeline {
+	return &fakePipeline{db: f.db, sent: f.sent}
+}
+
+func (f fakePipeliner) QueueRead(ctx context.Context, p Pipeline, args int, set func(int, error)) bool {
+	pl, ok := p.(*fakePipeline)
+	if !ok || pl.db != f.db {
+		return false
+	}
+	pl.reads = append(pl.reads, func() { set(args*10, nil) })
+	return true
+}
+
+func TestBatch_ReadAll(t *testing.T) {
+	var sent int32
+
+	db1 := fakePipeliner{db: "db1", sent: &sent}
+	db2 := fakePipeliner{db: "db2", sent: &sent}
+	plain := readerFunc[string, int](func(ctx context.Context, args int) (string, error) {
+		return "plain", nil
+	})
+
+	var b Batch
+
+	r1 := Queue[int, int](&b, db1, 1)
+	r2 := Queue[string, int](&b, plain, 0)
+	r3 := Queue[int, int](&b, db2, 3)
+	r4 := Queue[int, int](&b, db1, 4)
+
+	if b.Len() != 4 {
+		t.Fatalf("Len() = %d, want 4", b.Len())
+	}
+
+	if err := b.ReadAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if r1.Value != 10 || r2.Value != "plain" || r3.Value != 30 || r4.Value != 40 {
+		t.Errorf("ReadAll() results = %v, %v, %v, %v", r1.Value, r2.Value, r3.Value, r4.Value)
+	}
+
+	if sent != 2 {
+		t.Errorf("pipelines sent = %d, want 2", sent)
+	}
+
+	if b.Len() != 0 {
+		t.Errorf("Len() = %d after ReadAll, want 0", b.Len())
+	}
+}
+
+func TestBatch_ReadAll_Error(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	fail := func(err error) Reader[int, struct{}] {
+		return readerFunc[int, struct{}](func(ctx context.Context, args struct{}) (int, error) {
+			return 0, err
+		})
+	}
+	ok := readerFunc[int, struct{}](func(ctx context.Context, args struct{}) (int, error) {
+		return 1, nil
+	})
+
+	b := Batch{MaxConcurrency: 1}
+
+	r1 := Queue[int, struct{}](&b, ok, struct{}{})
+	r2 := Queue(&b, fail(errFirst), struct{}{})
+	r3 := Queue(&b, fail(errSecond), struct{}{})
+
+	err := b.ReadAll(context.Background())
+	if !errors.Is(err, errFirst) {
+		t.Errorf("ReadAll() error = %v, want %v", err, errFirst)
+	}
+
+	if r1.Err != nil || r1.Value != 1 || r2.Err != errFirst || r3.Err != errSecond {
+		t.Errorf("ReadAll() results = %+v, %+v, %+v", r1, r2, r3)
+	}
+}
+
+func TestBatch_ReadAll_MaxConcurrency(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		b    Batch
+		want int32
+	}{
+		{"limit", context.Background(), Batch{MaxConcurrency: 2}, 2},
+		{"serial reads", WithSerialReads(context.Background()), Batch{}, 1},
+		{"serial reads over limit", WithSerialReads(context.Background()), Batch{MaxConcurrency: 2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if max := maxConcurrentReads(t, tt.ctx, tt.b); max > tt.want {
+				t.Errorf("max concurrent reads = %d, want <= %d", max, tt.want)
+			}
+		})
+	}
+}
+
+func maxConcurrentReads(t *testing.T, ctx context.Context, b Batch) int32 {
+	var running, max int32
+
+	r := readerFunc[int, struct{}](func(ctx context.Context, args struct{}) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+
+		return 0, nil
+	})
+
+	for i := 0; i < 10; i++ {
+		Queue[int, struct{}](&b, r, struct{}{})
+	}
+
+	if err := b.ReadAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	return max
+}
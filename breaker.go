@@ -0,0 +1,416 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrCircuitOpen  = errors.New("circuit breaker is open")
+	ErrBulkheadFull = errors.New("too many concurrent operations")
+)
+
+// BreakerState is a state of the circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets operations run and records their outcomes.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects operations with ErrCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of probe operations run to check recovery.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerOption configures Breaker.
+type BreakerOption func(*Breaker)
+
+// WithFailureRate opens the breaker when the rate of failures among the last window operations
+// reaches rate, once at least min operations are recorded. It's 0.5 of 100 operations, at least 10, by default.
+// NewBreaker panics when window is smaller than min, since the breaker would never open.
+func WithFailureRate(rate float64, window, min int) BreakerOption {
+	return func(b *Breaker) {
+		b.rate = rate
+		b.window = make([]bool, window)
+		b.min = min
+	}
+}
+
+// WithOpenTimeout sets time the breaker stays open before probing, 30 seconds by default.
+func WithOpenTimeout(d time.Duration) BreakerOption {
+	return func(b *Breaker) {
+		b.openTimeout = d
+	}
+}
+
+// WithProbes sets number of successful probes closing the half-open breaker, 1 by default.
+// Probes run one at a time, the rest operations are rejected.
+func WithProbes(n int) BreakerOption {
+	return func(b *Breaker) {
+		b.probes = n
+	}
+}
+
+// WithFailure sets classifier of failed operations. By default any error is a failure except
+// errors of the caller or business rules: ErrNotFound, ErrNotAffected, *AffectedError, ErrBadCursor,
+// ErrMissingScope, ErrRateLimited, ErrBulkheadFull, ErrCircuitOpen, *ValidationError and canceled context.
+func WithFailure(isFailure func(err error) bool) BreakerOption {
+	return func(b *Breaker) {
+		b.isFailure = isFailure
+	}
+}
+
+// WithStateChange sets callback of breaker state changes, e.g. to export metrics.
+// It's called with the breaker locked, so it must not call the breaker.
+func WithStateChange(fn func(from, to BreakerState)) BreakerOption {
+	return func(b *Breaker) {
+		b.onChange = fn
+	}
+}
+
+// WithBreakerClock sets time source of the breaker, time.Now by default.
+func WithBreakerClock(now func() time.Time) BreakerOption {
+	return func(b *Breaker) {
+		b.now = now
+	}
+}
+
+// BreakerStats is a snapshot of the breaker for metrics.
+type BreakerStats struct {
+	State BreakerState
+	// Requests and Failures are numbers of operations in the window.
+	Requests int
+	Failures int
+	// Rejected is the total number of operations rejected by the open breaker.
+	Rejected uint64
+}
+
+// Breaker is a circuit breaker failing operations fast while the database is degraded.
+// A nil *Breaker runs operations as is.
+type Breaker struct {
+	rate        float64
+	min         int
+	openTimeout time.Duration
+	probes      int
+	isFailure   func(err error) bool
+	onChange    func(from, to BreakerState)
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	window   []bool // ring of outcomes, true is a failure
+	next     int
+	count    int
+	failures int
+	openedAt time.Time
+	probing  bool
+	passed   int
+	rejected uint64
+}
+
+// errPanicked is the outcome of operations which panicked.
+var errPanicked = errors.New("operation panicked")
+
+func NewBreaker(opts ...BreakerOption) *Breaker {
+	b := &Breaker{
+		rate:        0.5,
+		window:      make([]bool, 100),
+		min:         10,
+		openTimeout: 30 * time.Second,
+		probes:      1,
+		isFailure:   isFailure,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if len(b.window) < b.min {
+		panic(fmt.Sprintf("norm: breaker window %d is smaller than min %d", len(b.window), b.min))
+	}
+	return b
+}
+
+// Guard runs fn unless the breaker is open and records its outcome, a panic of fn is recorded as a failure.
+func (b *Breaker) Guard(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b == nil {
+		return fn(ctx)
+	}
+
+	probe, err := b.acquire()
+	if err != nil {
+		return err
+	}
+
+	done := false
+	defer func() {
+		if !done {
+			b.release(probe, errPanicked)
+		}
+	}()
+
+	err = fn(ctx)
+	done = true
+	b.release(probe, err)
+
+	return err
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	return b.Stats().State
+}
+
+// Stats returns snapshot of the breaker.
+func (b *Breaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.halfOpenIfExpired()
+
+	return BreakerStats{
+		State:    b.state,
+		Requests: b.count,
+		Failures: b.failures,
+		Rejected: b.rejected,
+	}
+}
+
+func (b *Breaker) acquire() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.halfOpenIfExpired()
+
+	switch b.state {
+	case BreakerOpen:
+		b.rejected++
+		return false, ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			b.rejected++
+			return false, ErrCircuitOpen
+		}
+		b.probing = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (b *Breaker) release(probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := err != nil && b.isFailure(err)
+
+	if probe {
+		b.probing = false
+
+		if errors.Is(err, ErrBulkheadFull) {
+			// the probe didn't reach the database
+			return
+		}
+
+		if failed {
+			b.open()
+			return
+		}
+
+		b.passed++
+		if b.passed >= b.probes {
+			b.reset()
+			b.setState(BreakerClosed)
+		}
+		return
+	}
+
+	if b.state != BreakerClosed {
+		// the operation started before the breaker opened
+		return
+	}
+
+	b.record(failed)
+
+	if b.count >= b.min && float64(b.failures) >= b.rate*float64(b.count) && b.failures > 0 {
+		b.open()
+	}
+}
+
+func (b *Breaker) record(failed bool) {
+	if len(b.window) == 0 {
+		return
+	}
+
+	if b.count == len(b.window) {
+		if b.window[b.next] {
+			b.failures--
+		}
+	} else {
+		b.count++
+	}
+
+	b.window[b.next] = failed
+	if failed {
+		b.failures++
+	}
+
+	b.next = (b.next + 1) % len(b.window)
+}
+
+func (b *Breaker) open() {
+	b.openedAt = b.now()
+	b.setState(BreakerOpen)
+}
+
+func (b *Breaker) reset() {
+	for i := range b.window {
+		b.window[i] = false
+	}
+	b.next, b.count, b.failures, b.passed = 0, 0, 0, 0
+}
+
+func (b *Breaker) halfOpenIfExpired() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		b.passed = 0
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+func (b *Breaker) setState(s BreakerState) {
+	if b.state == s {
+		return
+	}
+	from := b.state
+	b.state = s
+	if b.onChange != nil {
+		b.onChange(from, s)
+	}
+}
+
+func isFailure(err error) bool {
+	for _, target := range []error{
+		ErrNotFound,
+		ErrNotAffected,
+		ErrBadCursor,
+		ErrMissingScope,
+		ErrRateLimited,
+		ErrBulkheadFull,
+		ErrCircuitOpen,
+		context.Canceled,
+	} {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	var (
+		verr *ValidationError
+		aerr *AffectedError
+	)
+	return !errors.As(err, &verr) && !errors.As(err, &aerr)
+}
+
+// Bulkhead limits number of concurrent operations of an object,
+// so a degraded object can't take all connections of the pool.
+// A nil *Bulkhead runs operations as is.
+type Bulkhead struct {
+	sem      chan struct{}
+	wait     time.Duration
+	rejected atomic.Uint64
+}
+
+// NewBulkhead makes bulkhead of limit concurrent operations. An operation over the limit waits
+// for a free slot up to wait and fails with ErrBulkheadFull, zero wait rejects it immediately.
+func NewBulkhead(limit int, wait time.Duration) *Bulkhead {
+	return &Bulkhead{sem: make(chan struct{}, limit), wait: wait}
+}
+
+// Guard runs fn when there is a free slot.
+func (l *Bulkhead) Guard(ctx context.Context, fn func(ctx context.Context) error) error {
+	if l == nil {
+		return fn(ctx)
+	}
+
+	if err := l.acquire(ctx); err != nil {
+		return err
+	}
+	defer func() { <-l.sem }()
+
+	return fn(ctx)
+}
+
+// InFlight returns number of running operations.
+func (l *Bulkhead) InFlight() int {
+	return len(l.sem)
+}
+
+// Limit returns maximum number of concurrent operations.
+func (l *Bulkhead) Limit() int {
+	return cap(l.sem)
+}
+
+// Rejected returns total number of rejected operations.
+func (l *Bulkhead) Rejected() uint64 {
+	return l.rejected.Load()
+}
+
+func (l *Bulkhead) acquire(ctx context.Context) error {
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if l.wait <= 0 {
+		l.rejected.Add(1)
+		return ErrBulkheadFull
+	}
+
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	case <-timer.C:
+		l.rejected.Add(1)
+		return ErrBulkheadFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Resilience guards operations by the circuit breaker and the bulkhead, any of them may be nil.
+// Use one Resilience per object:
+//
+//	users = norm.GuardObject[User, UserArgs](users, norm.Resilience[UserArgs]{
+//		Breaker:  norm.NewBreaker(),
+//		Bulkhead: norm.NewBulkhead(10, 100*time.Millisecond),
+//	})
+type Resilience[A any] struct {
+	Breaker  *Breaker
+	Bulkhead *Bulkhead
+}
+
+func (r Resilience[A]) Guard(ctx context.Context, op Op, args A, fn func(ctx context.Context) error) error {
+	return r.Breaker.Guard(ctx, func(ctx context.Context) error {
+		return r.Bulkhead.Guard(ctx, fn)
+	})
+}
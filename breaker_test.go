@@ -0,0 +1,257 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestBreaker(t *testing.T) {
+	errDB := errors.New("db")
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	var changes []BreakerState
+
+	b := NewBreaker(
+		WithFailureRate(0.5, 4, 4),
+		WithOpenTimeout(time.Second),
+		WithProbes(2),
+		WithBreakerClock(clock.Now),
+		WithStateChange(func(from, to BreakerState) { changes = append(changes, to) }),
+	)
+
+	run := func(err error) error {
+		return b.Guard(context.Background(), func(ctx context.Context) error { return err })
+	}
+
+	// not found and successes aren't failures
+	for _, err := range []error{nil, ErrNotFound, errDB} {
+		if got := run(err); got != err {
+			t.Fatalf("Guard() error = %v, want %v", got, err)
+		}
+	}
+
+	if s := b.Stats(); s.State != BreakerClosed || s.Requests != 3 || s.Failures != 1 {
+		t.Fatalf("Stats() = %+v", s)
+	}
+
+	// the second failure of the 4 operations window opens the breaker
+	_ = run(errDB)
+
+	if err := run(nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Guard() error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+
+	if s := b.State(); s != BreakerHalfOpen {
+		t.Fatalf("State() = %v, want %v", s, BreakerHalfOpen)
+	}
+
+	// failed probe opens the breaker again
+	_ = run(errDB)
+
+	if s := b.State(); s != BreakerOpen {
+		t.Fatalf("State() = %v, want %v", s, BreakerOpen)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+
+	_ = run(nil)
+	_ = run(nil)
+
+	if s := b.Stats(); s.State != BreakerClosed || s.Requests != 0 || s.Rejected != 1 {
+		t.Fatalf("Stats() = %+v", s)
+	}
+
+	want := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("changes = %v, want %v", changes, want)
+		}
+	}
+}
+
+func TestBreaker_HalfOpenSingleProbe(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := NewBreaker(WithFailureRate(1, 1, 1), WithOpenTimeout(time.Second), WithBreakerClock(clock.Now))
+
+	_ = b.Guard(context.Background(), func(ctx context.Context) error { return errors.New("db") })
+
+	clock.now = clock.now.Add(time.Second)
+
+	err := b.Guard(context.Background(), func(ctx context.Context) error {
+		return b.Guard(ctx, func(ctx context.Context) error { return nil })
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("concurrent probe error = %v, want %v", err, ErrCircuitOpen)
+	}
+}
+
+func TestBreaker_PanickedProbe(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := NewBreaker(WithFailureRate(1, 1, 1), WithOpenTimeout(time.Second), WithBreakerClock(clock.Now))
+
+	_ = b.Guard(context.Background(), func(ctx context.Context) error { return errors.New("db") })
+
+	clock.now = clock.now.Add(time.Second)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic of the probe is not propagated")
+			}
+		}()
+		_ = b.Guard(context.Background(), func(ctx context.Context) error { panic("probe") })
+	}()
+
+	if s := b.State(); s != BreakerOpen {
+		t.Fatalf("State() after panicked probe = %v, want %v", s, BreakerOpen)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+
+	if err := b.Guard(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("Guard() error = %v", err)
+	}
+
+	if s := b.State(); s != BreakerClosed {
+		t.Fatalf("State() = %v, want %v", s, BreakerClosed)
+	}
+}
+
+func TestBreaker_CallerErrors(t *testing.T) {
+	errs := []error{
+		ErrNotFound,
+		ErrNotAffected,
+		&AffectedError{Policy: Exactly(1), Affected: 2},
+		ErrBadCursor,
+		ErrMissingScope,
+		ErrRateLimited,
+		&RateLimitError{RetryAfter: time.Second},
+		ErrBulkheadFull,
+		ErrCircuitOpen,
+		context.Canceled,
+		&ValidationError{},
+	}
+
+	b := NewBreaker(WithFailureRate(0.5, 1, 1))
+
+	for _, want := range errs {
+		if err := b.Guard(context.Background(), func(ctx context.Context) error { return want }); err != want {
+			t.Fatalf("Guard() error = %v, want %v", err, want)
+		}
+		if s := b.State(); s != BreakerClosed {
+			t.Fatalf("State() after %v = %v, want %v", want, s, BreakerClosed)
+		}
+	}
+}
+
+func TestNewBreaker_Panic_WindowSmallerThanMin(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	NewBreaker(WithFailureRate(0.5, 5, 10))
+}
+
+func TestBreaker_Nil(t *testing.T) {
+	var b *Breaker
+	if err := b.Guard(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBulkhead(t *testing.T) {
+	tests := []struct {
+		name string
+		wait time.Duration
+	}{
+		{name: "reject", wait: 0},
+		{name: "wait", wait: 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewBulkhead(1, tt.wait)
+
+			started := make(chan struct{})
+			done := make(chan struct{})
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = l.Guard(context.Background(), func(ctx context.Context) error {
+					close(started)
+					<-done
+					return nil
+				})
+			}()
+
+			<-started
+
+			if n := l.InFlight(); n != 1 {
+				t.Errorf("InFlight() = %d, want 1", n)
+			}
+
+			err := l.Guard(context.Background(), func(ctx context.Context) error { return nil })
+			if !errors.Is(err, ErrBulkheadFull) {
+				t.Errorf("Guard() error = %v, want %v", err, ErrBulkheadFull)
+			}
+
+			close(done)
+			wg.Wait()
+
+			if err := l.Guard(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+				t.Errorf("Guard() error = %v", err)
+			}
+
+			if n := l.Rejected(); n != 1 {
+				t.Errorf("Rejected() = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestGuardObject_Resilience(t *testing.T) {
+	obj := &memObject{values: map[int]auditModel{}}
+	b := NewBreaker(WithFailureRate(1, 1, 1))
+
+	g := GuardObject[auditModel, int](obj, Resilience[int]{Breaker: b, Bulkhead: NewBulkhead(1, 0)})
+
+	if err := g.Create(context.Background(), 1, auditModel{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := g.Read(context.Background(), 1)
+	if err != nil || v.Name != "a" {
+		t.Fatalf("Read() = %v, %v", v, err)
+	}
+
+	if err := g.Delete(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.Read(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Read() error = %v, want %v", err, ErrNotFound)
+	}
+
+	if s := b.State(); s != BreakerClosed {
+		t.Errorf("State() = %v, want %v", s, BreakerClosed)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	refRe      = regexp.MustCompile(`\.([MA])\.([A-Za-z_]\w*)`)
+	tableRe    = regexp.MustCompile(`(?is)\b(?:FROM|INTO|UPDATE)\s+([\w."]+)`)
+	compareRe  = regexp.MustCompile(`([\w."]+)\s*(?:=|<>|!=|<=|>=|<|>)\s*\{\{-?\s*\.([MA])\.(\w+)\s*-?\}\}`)
+	insertRe   = regexp.MustCompile(`(?is)\bINSERT\s+INTO\s+[\w."]+\s*\(`)
+	valuesRe   = regexp.MustCompile(`(?is)^\s*VALUES\s*\(`)
+	selectRe   = regexp.MustCompile(`(?i)\bSELECT\b`)
+	fromRe     = regexp.MustCompile(`(?i)\bFROM\b`)
+	aliasRe    = regexp.MustCompile(`(?is)\bAS\s+([\w"]+)\s*$`)
+	identEndRe = regexp.MustCompile(`([\w"]+)\s*$`)
+)
+
+// ref is a template field, e.g. {{ .M.Name }} is ref{"M", "Name"}.
+type ref struct {
+	root  string
+	field string
+}
+
+type analysis struct {
+	table   string
+	refs    []ref
+	columns map[ref]string
+	// selected are result columns of the query.
+	selected []string
+}
+
+func analyze(sql string) analysis {
+	a := analysis{
+		columns: map[ref]string{},
+	}
+
+	if m := tableRe.FindStringSubmatch(sql); m != nil {
+		a.table = unquote(m[1])
+	}
+
+	seen := map[ref]bool{}
+	for _, m := range refRe.FindAllStringSubmatch(sql, -1) {
+		r := ref{m[1], m[2]}
+		if !seen[r] {
+			seen[r] = true
+			a.refs = append(a.refs, r)
+		}
+	}
+
+	for _, m := range compareRe.FindAllStringSubmatch(sql, -1) {
+		a.columns[ref{m[2], m[3]}] = unquote(m[1])
+	}
+
+	a.analyzeInsert(sql)
+	a.selected = selectedColumns(sql)
+
+	return a
+}
+
+// analyzeInsert maps template fields in VALUES to the inserted columns.
+func (a *analysis) analyzeInsert(sql string) {
+	loc := insertRe.FindStringIndex(sql)
+	if loc == nil {
+		return
+	}
+
+	cols, ok := enclosed(sql[loc[1]-1:])
+	if !ok {
+		return
+	}
+
+	rest := sql[loc[1]+len(cols)+1:]
+
+	vloc := valuesRe.FindStringIndex(rest)
+	if vloc == nil {
+		return
+	}
+
+	values, ok := enclosed(rest[vloc[1]-1:])
+	if !ok {
+		return
+	}
+
+	colList := splitTopLevel(cols, ',')
+	valList := splitTopLevel(values, ',')
+
+	for i, v := range valList {
+		if i >= len(colList) {
+			break
+		}
+		if m := refRe.FindStringSubmatch(v); m != nil {
+			a.columns[ref{m[1], m[2]}] = unquote(colList[i])
+		}
+	}
+}
+
+// selectedColumns returns names of the result columns of the top level SELECT.
+func selectedColumns(sql string) (cols []string) {
+	loc := selectRe.FindStringIndex(sql)
+	if loc == nil {
+		return nil
+	}
+
+	list := sql[loc[1]:]
+	end := len(list)
+
+	// The first FROM outside of parentheses ends the columns list.
+	for _, m := range fromRe.FindAllStringIndex(list, -1) {
+		prefix := list[:m[0]]
+		if strings.Count(prefix, "(") == strings.Count(prefix, ")") {
+			end = m[0]
+			break
+		}
+	}
+
+	for _, item := range splitTopLevel(list[:end], ',') {
+		if m := aliasRe.FindStringSubmatch(item); m != nil {
+			cols = append(cols, unquote(m[1]))
+			continue
+		}
+		if m := identEndRe.FindStringSubmatch(item); m != nil {
+			cols = append(cols, unquote(m[1]))
+			continue
+		}
+		if strings.HasSuffix(strings.TrimSpace(item), "*") {
+			cols = append(cols, "*")
+		}
+	}
+
+	return cols
+}
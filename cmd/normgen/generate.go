@@ -0,0 +1,418 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+)
+
+type columnTypes struct {
+	schema schema
+	// results are result columns of read queries by query names.
+	results map[string][]column
+}
+
+// lookup finds column in the table, or in any table when the table is unknown.
+func (t columnTypes) lookup(table, name string) (column, bool) {
+	if tbl, ok := t.schema[table]; ok {
+		if c, ok := tbl.column(name); ok {
+			return c, true
+		}
+	}
+
+	tables := make([]string, 0, len(t.schema))
+	for name := range t.schema {
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+
+	for _, tbl := range tables {
+		if c, ok := t.schema[tbl].column(name); ok {
+			return c, true
+		}
+	}
+
+	return column{}, false
+}
+
+func (t columnTypes) result(query, name string) (column, bool) {
+	for _, c := range t.results[query] {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+type structField struct {
+	name string
+	typ  string
+	tag  string
+}
+
+type structType struct {
+	name   string
+	fields []*structField
+}
+
+func (s *structType) field(name string) *structField {
+	for _, f := range s.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// add adds field or refines type of the existing one.
+func (s *structType) add(f structField) {
+	if prev := s.field(f.name); prev != nil {
+		if prev.typ == "any" {
+			prev.typ = f.typ
+		}
+		return
+	}
+	s.fields = append(s.fields, &f)
+}
+
+type object struct {
+	name    string
+	model   string
+	many    bool
+	queries map[string]*query
+	args    *structType
+}
+
+type generator struct {
+	types   columnTypes
+	models  []*structType
+	objects []*object
+}
+
+func generate(pkg string, queries []*query, types columnTypes) ([]byte, error) {
+	g := generator{types: types}
+
+	if err := g.collect(queries); err != nil {
+		return nil, err
+	}
+
+	for _, o := range g.objects {
+		g.build(o)
+	}
+
+	return g.source(pkg)
+}
+
+func (g *generator) collect(queries []*query) error {
+	objects := map[string]*object{}
+
+	for _, q := range queries {
+		o, ok := objects[q.object]
+		if !ok {
+			o = &object{
+				name:    q.object,
+				model:   q.model,
+				queries: map[string]*query{},
+				args:    &structType{name: q.object + "Args"},
+			}
+			objects[q.object] = o
+			g.objects = append(g.objects, o)
+		}
+
+		if prev, ok := o.queries[q.op]; ok {
+			return fmt.Errorf("%s: object %s has %s query %q already declared at %s", q.pos, o.name, q.op, prev.name, prev.pos)
+		}
+
+		if o.model != q.model {
+			return fmt.Errorf("%s: object %s has model %s, but query %q has model %s", q.pos, o.name, o.model, q.name, q.model)
+		}
+
+		if q.many {
+			if q.op != "read" {
+				return fmt.Errorf("%s: only read query can return many models", q.pos)
+			}
+			o.many = true
+		}
+
+		o.queries[q.op] = q
+	}
+
+	return nil
+}
+
+func (g *generator) model(name string) *structType {
+	for _, m := range g.models {
+		if m.name == name {
+			return m
+		}
+	}
+	m := &structType{name: name}
+	g.models = append(g.models, m)
+	return m
+}
+
+// build collects fields of the object model and arguments.
+func (g *generator) build(o *object) {
+	model := g.model(o.model)
+
+	if q, ok := o.queries["read"]; ok {
+		a := analyze(q.sql)
+
+		for _, col := range a.selected {
+			cols := []string{col}
+
+			if col == "*" {
+				cols = nil
+				for _, c := range g.types.schema[a.table].columns {
+					cols = append(cols, c.name)
+				}
+			}
+
+			for _, col := range cols {
+				name := exported(col)
+				model.add(structField{
+					name: name,
+					typ:  g.fieldType(q, name, a.table, col),
+					tag:  col,
+				})
+			}
+		}
+	}
+
+	for _, op := range ops {
+		q, ok := o.queries[op]
+		if !ok {
+			continue
+		}
+
+		a := analyze(q.sql)
+
+		for _, r := range a.refs {
+			col, ok := a.columns[r]
+
+			switch r.root {
+			case "M":
+				if !ok {
+					col = normsql.SnakeCase(r.field)
+				}
+				model.add(structField{
+					name: r.field,
+					typ:  g.fieldType(q, r.field, a.table, col),
+					tag:  col,
+				})
+
+			case "A":
+				typ, annotated := q.args[r.field]
+				if !annotated {
+					typ = g.columnType(a.table, col, ok)
+				}
+				o.args.add(structField{name: r.field, typ: typ})
+			}
+		}
+	}
+}
+
+func (g *generator) fieldType(q *query, field, table, col string) string {
+	if typ, ok := q.fields[field]; ok {
+		return typ
+	}
+	if c, ok := g.types.result(q.name, col); ok {
+		return columnGoType(c)
+	}
+	return g.columnType(table, col, true)
+}
+
+func (g *generator) columnType(table, col string, known bool) string {
+	if !known {
+		return "any"
+	}
+	if c, ok := g.types.lookup(table, col); ok {
+		return columnGoType(c)
+	}
+	return "any"
+}
+
+func columnGoType(c column) string {
+	if c.nullable && c.goType != "any" && !strings.HasPrefix(c.goType, "[]") && c.goType != "json.RawMessage" {
+		return "*" + c.goType
+	}
+	return c.goType
+}
+
+func (g *generator) source(pkg string) ([]byte, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// Code generated by normgen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+
+	imports := g.imports()
+
+	b.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&b, "\t%q\n", imp)
+	}
+	if len(imports) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("\tnormsql \"github.com/WinPooh32/norm/driver/sql\"\n)\n")
+
+	for _, m := range g.models {
+		writeStruct(&b, m, true)
+	}
+
+	for _, o := range g.objects {
+		writeStruct(&b, o.args, false)
+	}
+
+	b.WriteString("\nconst (\n")
+	for _, o := range g.objects {
+		for _, op := range ops {
+			if q, ok := o.queries[op]; ok {
+				fmt.Fprintf(&b, "\t%s = %s\n", queryConst(q), literal(q.sql))
+			}
+		}
+	}
+	b.WriteString(")\n")
+
+	for _, o := range g.objects {
+		writeConstructor(&b, o)
+	}
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+func (g *generator) imports() []string {
+	var imports []string
+
+	var types []string
+	for _, m := range append(g.models, g.argsTypes()...) {
+		for _, f := range m.fields {
+			types = append(types, f.typ)
+		}
+	}
+
+	all := strings.Join(types, " ")
+
+	if strings.Contains(all, "json.") {
+		imports = append(imports, "encoding/json")
+	}
+	if strings.Contains(all, "time.") {
+		imports = append(imports, "time")
+	}
+
+	sort.Strings(imports)
+
+	return imports
+}
+
+func (g *generator) argsTypes() (types []*structType) {
+	for _, o := range g.objects {
+		types = append(types, o.args)
+	}
+	return types
+}
+
+func writeStruct(b *strings.Builder, s *structType, tags bool) {
+	fmt.Fprintf(b, "\ntype %s struct {\n", s.name)
+	for _, f := range s.fields {
+		if tags {
+			fmt.Fprintf(b, "\t%s %s `db:%q`\n", f.name, f.typ, f.tag)
+		} else {
+			fmt.Fprintf(b, "\t%s %s\n", f.name, f.typ)
+		}
+	}
+	b.WriteString("}\n")
+}
+
+func writeConstructor(b *strings.Builder, o *object) {
+	model := o.model
+	if o.many {
+		model = "[]" + model
+	}
+
+	var (
+		kind string
+		ops  []string
+	)
+
+	_, c := o.queries["create"]
+	_, u := o.queries["update"]
+	_, d := o.queries["delete"]
+
+	switch {
+	case d:
+		kind, ops = "Object", []string{"create", "read", "update", "delete"}
+	case u:
+		kind, ops = "PersistentObject", []string{"create", "read", "update"}
+	case c:
+		kind, ops = "ImmutableObject", []string{"create", "read"}
+	default:
+		kind, ops = "View", []string{"read"}
+	}
+
+	args := []string{"db"}
+	for _, op := range ops {
+		if q, ok := o.queries[op]; ok {
+			args = append(args, queryConst(q))
+		} else {
+			args = append(args, `""`)
+		}
+	}
+	args = append(args, "opts...")
+
+	fmt.Fprintf(b, "\nfunc New%s(db normsql.DB, opts ...normsql.Option) normsql.%s[%s, %s] {\n", o.name, kind, model, o.args.name)
+	fmt.Fprintf(b, "\treturn normsql.New%s[%s, %s](%s)\n}\n", kind, model, o.args.name, strings.Join(args, ", "))
+}
+
+func queryConst(q *query) string {
+	r := []rune(q.name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r) + "Query"
+}
+
+func literal(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`\n" + s + "\n`"
+}
+
+var initialisms = map[string]bool{
+	"id": true, "ip": true, "url": true, "uri": true, "uuid": true, "api": true,
+	"http": true, "https": true, "json": true, "sql": true, "db": true,
+}
+
+// exported makes exported Go identifier of the column name, e.g. user_id to UserID.
+func exported(col string) string {
+	parts := strings.FieldsFunc(col, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+
+	for _, p := range parts {
+		if initialisms[strings.ToLower(p)] {
+			b.WriteString(strings.ToUpper(p))
+			continue
+		}
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "C" + name
+	}
+
+	return name
+}
@@ -0,0 +1,13 @@
+module github.com/WinPooh32/norm/cmd/normgen
+
+go 1.19
+
+require (
+	github.com/WinPooh32/norm/driver/sql v0.0.0-20261018231856-3db48776d691
+	github.com/lib/pq v1.10.9
+)
+
+require (
+	github.com/VauntDev/tqla v0.0.1 // indirect
+	github.com/WinPooh32/norm v0.1.2-0.20261018231856-3db48776d691 // indirect
+)
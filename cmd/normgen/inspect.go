@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+var actionRe = regexp.MustCompile(`\{\{-?\s*(\w*)[^}]*\}\}`)
+
+// inspectResults runs read queries in a rolled back transaction with NULL arguments
+// and collects types of their result columns.
+func inspectResults(dsn string, queries []*query) (map[string][]column, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	results := map[string][]column{}
+
+	for _, q := range queries {
+		if q.op != "read" {
+			continue
+		}
+
+		cols, err := inspectQuery(ctx, db, q)
+		if err != nil {
+			return nil, fmt.Errorf("%s: inspect %q: %w", q.pos, q.name, err)
+		}
+
+		results[q.name] = cols
+	}
+
+	return results, nil
+}
+
+func inspectQuery(ctx context.Context, db *sql.DB, q *query) ([]column, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, renderNulls(q.sql))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]column, len(types))
+
+	for i, t := range types {
+		goType, nullable := scanGoType(t.ScanType())
+		if n, ok := t.Nullable(); ok {
+			nullable = n
+		}
+
+		cols[i] = column{
+			name:     t.Name(),
+			goType:   goType,
+			nullable: nullable,
+		}
+	}
+
+	return cols, nil
+}
+
+// renderNulls replaces template actions by NULL values, so the query can be run
+// for inspecting its result columns. Clauses made by the template functions are dropped.
+func renderNulls(tpl string) string {
+	return actionRe.ReplaceAllStringFunc(tpl, func(action string) string {
+		if strings.HasPrefix(strings.TrimLeft(action, "{-"), "/*") {
+			return ""
+		}
+
+		switch word := actionRe.FindStringSubmatch(action)[1]; word {
+		case "in":
+			return "(NULL)"
+		case "if", "else", "end", "range", "with", "define", "template", "block",
+			"where", "orderBy", "ident", "values", "cond", "all":
+			return ""
+		default:
+			return "NULL"
+		}
+	})
+}
+
+// scanGoType returns Go type of the scan type, sql.Null* types are nullable.
+func scanGoType(t reflect.Type) (goType string, nullable bool) {
+	if t == nil {
+		return "any", false
+	}
+
+	switch s := t.String(); s {
+	case "[]uint8":
+		return "[]byte", false
+	case "interface {}":
+		return "any", false
+	case "sql.NullString":
+		return "string", true
+	case "sql.NullInt64", "sql.NullInt32", "sql.NullInt16", "sql.NullFloat64", "sql.NullBool", "sql.NullByte":
+		return strings.ToLower(strings.TrimPrefix(s, "sql.Null")), true
+	case "sql.NullTime":
+		return "time.Time", true
+	default:
+		return s, false
+	}
+}
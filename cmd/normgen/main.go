@@ -0,0 +1,131 @@
+// Command normgen generates model and arguments types with driver/sql constructors from annotated SQL files.
+//
+// Usage:
+//
+//	normgen [-pkg name] [-out file.go] [-schema schema.sql] [-dsn url] files...
+//
+// Queries are declared as for normsql.LoadQueries and annotated by comments:
+//
+//	-- name: GetUser
+//	-- object: User read
+//	SELECT "id", "name" FROM "users" WHERE "id" = {{ .A.ID }};
+//
+// Annotations:
+//
+//	-- object: <Object> <create|read|update|delete> [many]
+//		binds query to the object operation, "many" makes read return slice of models;
+//	-- model: <Type>
+//		name of the model type, the object name by default;
+//	-- arg: <Field> <type>
+//		type of the arguments field;
+//	-- field: <Field> <type>
+//		type of the model field.
+//
+// Types of fields which are not annotated are taken from result columns of read queries
+// and columns compared with or inserted from template fields. Column types are looked up
+// in CREATE TABLE statements of the -schema file or in a live PostgreSQL database given by -dsn.
+// Unknown types are generated as any.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("normgen: ")
+
+	var (
+		pkg        = flag.String("pkg", "", "package name of the generated file, the output directory name by default")
+		out        = flag.String("out", "", "output file, stdout by default")
+		schemaFile = flag.String("schema", "", "SQL file with CREATE TABLE statements")
+		dsn        = flag.String("dsn", "", "PostgreSQL connection URL for inspecting result columns")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: normgen [flags] files...\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*pkg, *out, *schemaFile, *dsn, flag.Args()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(pkg, out, schemaFile, dsn string, files []string) error {
+	var queries []*query
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		qq, err := parseQueries(file, data)
+		if err != nil {
+			return err
+		}
+
+		queries = append(queries, qq...)
+	}
+
+	var types columnTypes
+
+	if schemaFile != "" {
+		data, err := os.ReadFile(schemaFile)
+		if err != nil {
+			return err
+		}
+
+		types.schema, err = parseSchema(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %w", schemaFile, err)
+		}
+	}
+
+	if dsn != "" {
+		var err error
+
+		types.results, err = inspectResults(dsn, queries)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pkg == "" {
+		dir := "."
+		if out != "" {
+			dir = filepath.Dir(out)
+		}
+
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		pkg = filepath.Base(abs)
+	}
+
+	src, err := generate(pkg, queries, types)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := os.ReadFile("testdata/users.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries, err := parseQueries("testdata/users.sql", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemaData, err := os.ReadFile("testdata/schema.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := parseSchema(string(schemaData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generate("users", queries, columnTypes{schema: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("testdata/users.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerate_WithoutSchema(t *testing.T) {
+	queries, err := parseQueries("q.sql", []byte(`
+-- name: GetThing
+-- object: Thing read
+-- field: Count int64
+SELECT "id", count(*) AS "count" FROM "things" WHERE "id" = {{ .A.ID }} GROUP BY "id";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generate("things", queries, columnTypes{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"ID    any   `db:\"id\"`",
+		"Count int64 `db:\"count\"`",
+		"func NewThing(db normsql.DB, opts ...normsql.Option) normsql.View[Thing, ThingArgs]",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("generate() =\n%s\nwant to contain %q", got, want)
+		}
+	}
+}
+
+func TestParseQueries_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"no object", "-- name: Q\nSELECT 1;", `q.sql:1: query "Q" has no object annotation`},
+		{"bad operation", "-- name: Q\n-- object: T list\nSELECT 1;", `q.sql:2: unknown operation "list"`},
+		{"without name", "SELECT 1;", "q.sql:1: query without name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseQueries("q.sql", []byte(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseQueries() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerate_Error_DuplicateOperation(t *testing.T) {
+	queries, err := parseQueries("q.sql", []byte(`
+-- name: A
+-- object: T read
+SELECT 1 AS "a";
+
+-- name: B
+-- object: T read
+SELECT 2 AS "b";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = generate("p", queries, columnTypes{})
+	if err == nil || !strings.Contains(err.Error(), `object T has read query "A" already declared at q.sql:2`) {
+		t.Errorf("generate() error = %v", err)
+	}
+}
+
+func TestSelectedColumns(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []string
+	}{
+		{`SELECT "id", "t"."name" FROM "t"`, []string{"id", "name"}},
+		{`SELECT count(*) AS "n", (SELECT 1 FROM "x") AS one FROM "t"`, []string{"n", "one"}},
+		{`select * from t`, []string{"*"}},
+		{`INSERT INTO "t" VALUES (1)`, nil},
+	}
+	for _, tt := range tests {
+		if got := selectedColumns(tt.sql); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("selectedColumns(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestGoType(t *testing.T) {
+	tests := map[string]string{
+		"integer":                  "int32",
+		"BIGINT":                   "int64",
+		"varchar(64)":              "string",
+		"numeric(10, 2)":           "float64",
+		"timestamp with time zone": "time.Time",
+		"text[]":                   "[]string",
+		"jsonb":                    "json.RawMessage",
+		"point":                    "any",
+	}
+	for in, want := range tests {
+		if got := goType(in); got != want {
+			t.Errorf("goType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderNulls(t *testing.T) {
+	got := renderNulls(`SELECT "id" FROM "t" {{/* c */}}{{ where (cond "\"id\" = ?" .A.ID) }} AND "x" IN {{ in .A.IDs }} AND "y" = {{ .A.Y }}`)
+	want := `SELECT "id" FROM "t"  AND "x" IN (NULL) AND "y" = NULL`
+
+	if got != want {
+		t.Errorf("renderNulls() = %q, want %q", got, want)
+	}
+}
+
+func TestExported(t *testing.T) {
+	tests := map[string]string{
+		"id":         "ID",
+		"user_id":    "UserID",
+		"created_at": "CreatedAt",
+		"field_a":    "FieldA",
+		"1st":        "C1st",
+	}
+	for in, want := range tests {
+		if got := exported(in); got != want {
+			t.Errorf("exported(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	nameRe       = regexp.MustCompile(`^--\s*name:\s*(\S+)\s*$`)
+	annotationRe = regexp.MustCompile(`^--\s*(object|model|arg|field):\s*(.*?)\s*$`)
+)
+
+var ops = []string{"create", "read", "update", "delete"}
+
+type query struct {
+	name string
+	pos  string
+
+	object string
+	op     string
+	many   bool
+	model  string
+	args   map[string]string
+	fields map[string]string
+
+	sql string
+}
+
+// parseQueries reads named queries and their annotations. Comment lines aren't included into query text.
+func parseQueries(file string, data []byte) (queries []*query, err error) {
+	var (
+		q    *query
+		body strings.Builder
+	)
+
+	flush := func() error {
+		if q == nil {
+			return nil
+		}
+
+		q.sql = strings.TrimSpace(body.String())
+		body.Reset()
+
+		if q.sql == "" {
+			return fmt.Errorf("%s: query %q is empty", q.pos, q.name)
+		}
+
+		if q.object == "" {
+			return fmt.Errorf("%s: query %q has no object annotation", q.pos, q.name)
+		}
+
+		if q.model == "" {
+			q.model = q.object
+		}
+
+		queries = append(queries, q)
+
+		return nil
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		pos := fmt.Sprintf("%s:%d", file, line)
+
+		if m := nameRe.FindStringSubmatch(text); m != nil {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			q = &query{
+				name:   m[1],
+				pos:    pos,
+				args:   map[string]string{},
+				fields: map[string]string{},
+			}
+			continue
+		}
+
+		if m := annotationRe.FindStringSubmatch(text); m != nil && q != nil {
+			if err := q.annotate(m[1], m[2]); err != nil {
+				return nil, fmt.Errorf("%s: %w", pos, err)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(text, "--") {
+			continue
+		}
+
+		if q == nil {
+			if text != "" {
+				return nil, fmt.Errorf("%s: query without name", pos)
+			}
+			continue
+		}
+
+		body.WriteString(sc.Text())
+		body.WriteByte('\n')
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
+
+func (q *query) annotate(key, value string) error {
+	words := strings.Fields(value)
+
+	switch key {
+	case "object":
+		if len(words) < 2 || len(words) > 3 || (len(words) == 3 && words[2] != "many") {
+			return fmt.Errorf("object annotation must be: <Object> <operation> [many]")
+		}
+		if !isOp(words[1]) {
+			return fmt.Errorf("unknown operation %q, must be one of %s", words[1], strings.Join(ops, ", "))
+		}
+		q.object, q.op, q.many = words[0], words[1], len(words) == 3
+
+	case "model":
+		if len(words) != 1 {
+			return fmt.Errorf("model annotation must be: <Type>")
+		}
+		q.model = words[0]
+
+	case "arg", "field":
+		if len(words) < 2 {
+			return fmt.Errorf("%s annotation must be: <Field> <type>", key)
+		}
+		typ := strings.Join(words[1:], " ")
+		if key == "arg" {
+			q.args[words[0]] = typ
+		} else {
+			q.fields[words[0]] = typ
+		}
+	}
+
+	return nil
+}
+
+func isOp(op string) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type column struct {
+	name     string
+	goType   string
+	nullable bool
+}
+
+type table struct {
+	columns []column
+}
+
+func (t table) column(name string) (column, bool) {
+	for _, c := range t.columns {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return column{}, false
+}
+
+// schema is tables by names.
+type schema map[string]table
+
+var createTableRe = regexp.MustCompile(`(?is)\bCREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([\w."]+)\s*\(`)
+
+// parseSchema reads columns of CREATE TABLE statements.
+func parseSchema(src string) (schema, error) {
+	s := schema{}
+
+	for _, m := range createTableRe.FindAllStringSubmatchIndex(src, -1) {
+		name := unquote(src[m[2]:m[3]])
+
+		body, ok := enclosed(src[m[1]-1:])
+		if !ok {
+			return nil, fmt.Errorf("table %q: unbalanced parentheses", name)
+		}
+
+		var t table
+
+		for _, def := range splitTopLevel(body, ',') {
+			c, ok := parseColumn(def)
+			if ok {
+				t.columns = append(t.columns, c)
+			}
+		}
+
+		s[name] = t
+	}
+
+	return s, nil
+}
+
+var constraintWords = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "FOREIGN": true, "CHECK": true, "EXCLUDE": true, "LIKE": true,
+}
+
+var columnOptionWords = map[string]bool{
+	"NOT": true, "NULL": true, "PRIMARY": true, "DEFAULT": true, "REFERENCES": true, "UNIQUE": true,
+	"CHECK": true, "CONSTRAINT": true, "GENERATED": true, "COLLATE": true,
+}
+
+func parseColumn(def string) (column, bool) {
+	words := strings.Fields(def)
+	if len(words) < 2 || constraintWords[strings.ToUpper(words[0])] {
+		return column{}, false
+	}
+
+	var typeWords []string
+
+	for _, w := range words[1:] {
+		if columnOptionWords[strings.ToUpper(w)] {
+			break
+		}
+		typeWords = append(typeWords, w)
+	}
+
+	upper := strings.ToUpper(def)
+	notNull := strings.Contains(upper, "NOT NULL") || strings.Contains(upper, "PRIMARY KEY")
+
+	return column{
+		name:     unquote(words[0]),
+		goType:   goType(strings.Join(typeWords, " ")),
+		nullable: !notNull,
+	}, true
+}
+
+var typeParamsRe = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// goType maps SQL column type to Go type, unknown types are mapped to any.
+func goType(sqlType string) string {
+	t := strings.ToLower(typeParamsRe.ReplaceAllString(sqlType, ""))
+
+	if strings.HasSuffix(t, "[]") {
+		elem := goType(strings.TrimSuffix(t, "[]"))
+		if elem == "any" {
+			return "any"
+		}
+		return "[]" + elem
+	}
+
+	switch {
+	case t == "smallint" || t == "int2" || t == "smallserial":
+		return "int16"
+	case t == "integer" || t == "int" || t == "int4" || t == "serial":
+		return "int32"
+	case t == "bigint" || t == "int8" || t == "bigserial":
+		return "int64"
+	case t == "boolean" || t == "bool":
+		return "bool"
+	case t == "real" || t == "float4":
+		return "float32"
+	case t == "double precision" || t == "float8" || t == "float" || t == "numeric" || t == "decimal":
+		return "float64"
+	case t == "text" || t == "uuid" || t == "citext" ||
+		strings.HasPrefix(t, "varchar") || strings.HasPrefix(t, "character") || strings.HasPrefix(t, "char"):
+		return "string"
+	case t == "bytea" || t == "blob":
+		return "[]byte"
+	case t == "json" || t == "jsonb":
+		return "json.RawMessage"
+	case strings.HasPrefix(t, "timestamp") || t == "date" || strings.HasPrefix(t, "time"):
+		return "time.Time"
+	default:
+		return "any"
+	}
+}
+
+// enclosed returns text inside the parentheses which s starts with.
+func enclosed(s string) (string, bool) {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// splitTopLevel splits s by sep which are not enclosed into parentheses or quotes.
+func splitTopLevel(s string, sep rune) (parts []string) {
+	var (
+		depth int
+		quote rune
+		start int
+	)
+
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		parts = append(parts, last)
+	}
+
+	return parts
+}
+
+func unquote(ident string) string {
+	ident = strings.ReplaceAll(ident, `"`, "")
+	if i := strings.LastIndexByte(ident, '.'); i >= 0 {
+		ident = ident[i+1:]
+	}
+	return ident
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "users" (
+	"id" uuid PRIMARY KEY,
+	"name" varchar(255) NOT NULL,
+	"email" text,
+	"meta" jsonb NOT NULL DEFAULT '{}',
+	"created_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+	CONSTRAINT "users_name_key" UNIQUE ("name")
+);
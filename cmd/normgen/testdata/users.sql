@@ -0,0 +1,31 @@
+-- Users queries.
+
+-- name: CreateUser
+-- object: User create
+INSERT INTO "users" ("id", "name", "email", "meta", "created_at")
+VALUES ({{ .A.ID }}, {{ .M.Name }}, {{ .M.Email }}, {{ .M.Meta }}, {{ .A.Now }});
+
+-- name: GetUser
+-- object: User read
+SELECT "id", "name", "email", "meta", "created_at"
+FROM "users"
+WHERE "id" = {{ .A.ID }};
+
+-- name: UpdateUser
+-- object: User update
+UPDATE "users"
+SET "name" = {{ .M.Name }}, "email" = {{ .M.Email }}
+WHERE "id" = {{ .A.ID }};
+
+-- name: DeleteUser
+-- object: User delete
+DELETE FROM "users" WHERE "id" = {{ .A.ID }};
+
+-- name: ListUsers
+-- object: UserList read many
+-- model: UserShort
+-- arg: IDs []string
+SELECT "u"."id", upper("u"."name") AS "name"
+FROM "users" "u"
+WHERE "u"."id" IN {{ in .A.IDs }}
+ORDER BY "u"."id";
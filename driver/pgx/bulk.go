@@ -0,0 +1,147 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+)
+
+// BulkDB is implemented by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type BulkDB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Bulk loads large amounts of M values into the table by the COPY protocol of pgx,
+// columns are the fields of M mapped the same way as by reads.
+type Bulk[M any] struct {
+	db       BulkDB
+	table    pgx.Identifier
+	columns  []string
+	index    [][]int
+	progress normsql.Progress
+}
+
+// NewBulk makes loader of values into the table, it may be qualified by schema, e.g. "public.users".
+// It panics if M has fields without table column, see normsql.Mapper.WriteColumns.
+func NewBulk[M any](db BulkDB, table string, opts ...Option) Bulk[M] {
+	o := newOptions(opts)
+
+	b := Bulk[M]{
+		db:       db,
+		table:    pgx.Identifier(strings.Split(table, ".")),
+		progress: o.progress,
+	}
+
+	if t := reflect.TypeOf((*M)(nil)).Elem(); isRow(t) {
+		columns, index, err := o.mapper().WriteColumns(t)
+		if err != nil {
+			panic("pgx: NewBulk: " + err.Error())
+		}
+		b.columns, b.index = columns, index
+	}
+
+	return b
+}
+
+// Columns returns loaded columns in order of M fields.
+func (b Bulk[M]) Columns() []string {
+	return b.columns
+}
+
+// Load loads all values of the iterator and returns number of loaded values.
+// It runs in the context transaction, otherwise in its own one, so values are loaded completely or not at all.
+// With WithProgress values are copied by chunks of the progress interval, which is reported after every chunk.
+func (b Bulk[M]) Load(ctx context.Context, values normsql.Iterator[M]) (_ int64, err error) {
+	if len(b.columns) == 0 {
+		return 0, fmt.Errorf("no columns to load")
+	}
+
+	tx := txValue(ctx)
+	if tx == nil {
+		tx, err = b.db.Begin(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("begin transaction: %w", err)
+		}
+		defer tx.Rollback(ctx)
+	}
+
+	p := b.progress
+	src := &copySource[M]{ctx: ctx, values: values, index: b.index, limit: p.Every}
+
+	for !src.eof {
+		src.count = 0
+
+		n, err := tx.CopyFrom(ctx, b.table, b.columns, src)
+		if err != nil {
+			return 0, fmt.Errorf("copy rows: %w", err)
+		}
+
+		p.Add(n)
+	}
+
+	if txValue(ctx) == nil {
+		if err := tx.Commit(ctx); err != nil {
+			return 0, fmt.Errorf("commit transaction: %w", err)
+		}
+	}
+
+	return p.Done(), nil
+}
+
+// copySource yields values of the iterator to pgx.CopyFrom, up to limit values per copy when limit is positive.
+type copySource[M any] struct {
+	ctx    context.Context
+	values normsql.Iterator[M]
+	index  [][]int
+	limit  int64
+	count  int64
+	row    []any
+	eof    bool
+	err    error
+}
+
+func (s *copySource[M]) Next() bool {
+	if s.eof || s.err != nil || s.limit > 0 && s.count == s.limit {
+		return false
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		return false
+	}
+
+	value, err := s.values.Next(s.ctx)
+	if errors.Is(err, io.EOF) {
+		s.eof = true
+		return false
+	}
+	if err != nil {
+		s.err = err
+		return false
+	}
+
+	v := reflect.ValueOf(value)
+	s.row = make([]any, len(s.index))
+	for i, index := range s.index {
+		s.row[i] = v.FieldByIndex(index).Interface()
+	}
+
+	s.count++
+
+	return true
+}
+
+func (s *copySource[M]) Values() ([]any, error) {
+	return s.row, nil
+}
+
+func (s *copySource[M]) Err() error {
+	return s.err
+}
@@ -0,0 +1,97 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+)
+
+func TestNewBulk_Columns(t *testing.T) {
+	type base struct {
+		ID string `db:"id"`
+	}
+	type model struct {
+		base
+		Name    string `db:"name"`
+		Skipped string `db:"-"`
+		private string
+		Age     int `db:"age"`
+	}
+
+	b := NewBulk[model](nil, "public.models")
+
+	if want := []string{"id", "name", "age"}; !reflect.DeepEqual(b.Columns(), want) {
+		t.Errorf("Columns() = %v, want %v", b.Columns(), want)
+	}
+	if want := [][]int{{0, 0}, {1}, {4}}; !reflect.DeepEqual(b.index, want) {
+		t.Errorf("index = %v, want %v", b.index, want)
+	}
+
+	if snake := NewBulk[struct{ FieldID int }](nil, "t", WithSnakeCase()); !reflect.DeepEqual(snake.Columns(), []string{"field_id"}) {
+		t.Errorf("Columns() with snake case = %v", snake.Columns())
+	}
+}
+
+func TestNewBulk_Panic_Nested(t *testing.T) {
+	type author struct {
+		Name string `db:"name"`
+	}
+	type post struct {
+		ID     string `db:"id"`
+		Author author `db:"author"`
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	NewBulk[post](nil, "posts")
+}
+
+func TestCopySource(t *testing.T) {
+	type row struct {
+		ID int `db:"id"`
+	}
+
+	src := &copySource[row]{
+		ctx:    context.Background(),
+		values: normsql.SliceIterator([]row{{1}, {2}, {3}}),
+		index:  [][]int{{0}},
+		limit:  2,
+	}
+
+	var chunks [][]any
+
+	for !src.eof {
+		src.count = 0
+
+		var chunk []any
+		for src.Next() {
+			values, _ := src.Values()
+			chunk = append(chunk, values[0])
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if want := [][]any{{1, 2}, {3}}; !reflect.DeepEqual(chunks, want) {
+		t.Errorf("chunks = %v, want %v", chunks, want)
+	}
+}
+
+func TestCopySource_Error_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	src := &copySource[struct{}]{ctx: ctx, values: normsql.SliceIterator([]struct{}{{}})}
+
+	if src.Next() {
+		t.Fatal("Next() = true after cancel")
+	}
+	if !errors.Is(src.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want %v", src.Err(), context.Canceled)
+	}
+}
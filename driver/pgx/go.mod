@@ -0,0 +1,17 @@
+module github.com/WinPooh32/norm/driver/pgx
+
+go 1.19
+
+require (
+	github.com/VauntDev/tqla v0.0.1
+	github.com/WinPooh32/norm v0.1.2-0.20261018231856-3db48776d691
+	github.com/WinPooh32/norm/driver/sql v0.0.0-20261018231856-3db48776d691
+	github.com/jackc/pgx/v5 v5.4.3
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	golang.org/x/crypto v0.9.0 // indirect
+	golang.org/x/text v0.9.0 // indirect
+)
@@ -0,0 +1,81 @@
+package pgx
+
+import (
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+)
+
+// Option configures objects made by the package constructors.
+type Option func(*options)
+
+type options struct {
+	affected map[norm.Op]norm.RowsPolicy
+	progress normsql.Progress
+	columns  []normsql.Option
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithColumnTag is normsql.WithColumnTag for objects of the package.
+func WithColumnTag(tag string) Option {
+	return func(o *options) {
+		o.columns = append(o.columns, normsql.WithColumnTag(tag))
+	}
+}
+
+// WithSnakeCase is normsql.WithSnakeCase for objects of the package.
+func WithSnakeCase() Option {
+	return func(o *options) {
+		o.columns = append(o.columns, normsql.WithSnakeCase())
+	}
+}
+
+// WithLenientColumns is normsql.WithLenientColumns for objects of the package.
+// There is no WithConverter, custom types are scanned by pgx type codecs registered on the connection.
+func WithLenientColumns() Option {
+	return func(o *options) {
+		o.columns = append(o.columns, normsql.WithLenientColumns())
+	}
+}
+
+// mapper returns mapper of columns to struct fields shared with the sql driver.
+func (o options) mapper() normsql.Mapper {
+	return normsql.NewMapper(o.columns...)
+}
+
+// WithAffectedRows sets policy of rows affected by write operations ops, or by all writes when ops are omitted.
+// By default a write must affect at least one row, otherwise it fails with norm.ErrNotAffected.
+func WithAffectedRows(p norm.RowsPolicy, ops ...norm.Op) Option {
+	return func(o *options) {
+		if o.affected == nil {
+			o.affected = map[norm.Op]norm.RowsPolicy{}
+		}
+		if len(ops) == 0 {
+			ops = []norm.Op{norm.OpCreate, norm.OpUpdate, norm.OpDelete}
+		}
+		for _, op := range ops {
+			o.affected[op] = p
+		}
+	}
+}
+
+func (o options) rows(op norm.Op) norm.RowsPolicy {
+	if p, ok := o.affected[op]; ok {
+		return p
+	}
+	return norm.AtLeast(1)
+}
+
+// WithProgress makes Bulk loader copy values by chunks of every values and call fn with number of written values
+// after every chunk and at the end of load.
+func WithProgress(every int64, fn func(n int64)) Option {
+	return func(o *options) {
+		o.progress = normsql.Progress{Every: every, Fn: fn}
+	}
+}
@@ -0,0 +1,254 @@
+// Package pgx implements norm objects on top of the native pgx driver.
+//
+// Queries are the same templates as of the sql driver, where `.M` is the model and `.A` is the arguments.
+// Slices are passed as Postgres arrays, e.g. `"id" = ANY({{ .A.IDs }})`.
+// Columns are mapped to struct fields by normsql.Mapper, so models are shared by both drivers.
+// A slice model receives all rows, or elements of the single array column, e.g. of `SELECT array_agg("id")`.
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/VauntDev/tqla"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+)
+
+// DB is implemented by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type DB interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+type txKey struct{}
+
+// WithTransaction makes objects run queries of the context in tx instead of their DB.
+// Reads of norm.Batch run one by one then, as tx is unsafe for concurrent use.
+func WithTransaction(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(norm.WithSerialReads(ctx), txKey{}, tx)
+}
+
+func txValue(ctx context.Context) pgx.Tx {
+	tx := ctx.Value(txKey{})
+	if tx == nil {
+		return nil
+	}
+	return tx.(pgx.Tx)
+}
+
+func conn(ctx context.Context, db DB) DB {
+	if tx := txValue(ctx); tx != nil {
+		return tx
+	}
+	return db
+}
+
+type Object[M, A any] struct {
+	creator[M, A]
+	reader[M, A]
+	updater[M, A]
+	deleter[M, A]
+}
+
+type PersistentObject[M, A any] struct {
+	creator[M, A]
+	reader[M, A]
+	updater[M, A]
+}
+
+type ImmutableObject[M, A any] struct {
+	creator[M, A]
+	reader[M, A]
+}
+
+type View[M, A any] struct {
+	reader[M, A]
+}
+
+func NewObject[M, A any](db DB, c, r, u, d string, opts ...Option) Object[M, A] {
+	o := newOptions(opts)
+	return Object[M, A]{
+		creator: creator[M, A]{writer[M, A]{db, c, o.rows(norm.OpCreate)}},
+		reader:  reader[M, A]{db, r, o.mapper()},
+		updater: updater[M, A]{writer[M, A]{db, u, o.rows(norm.OpUpdate)}},
+		deleter: deleter[M, A]{writer[M, A]{db, d, o.rows(norm.OpDelete)}},
+	}
+}
+
+func NewPersistentObject[M, A any](db DB, c, r, u string, opts ...Option) PersistentObject[M, A] {
+	o := newOptions(opts)
+	return PersistentObject[M, A]{
+		creator: creator[M, A]{writer[M, A]{db, c, o.rows(norm.OpCreate)}},
+		reader:  reader[M, A]{db, r, o.mapper()},
+		updater: updater[M, A]{writer[M, A]{db, u, o.rows(norm.OpUpdate)}},
+	}
+}
+
+func NewImmutableObject[M, A any](db DB, c, r string, opts ...Option) ImmutableObject[M, A] {
+	o := newOptions(opts)
+	return ImmutableObject[M, A]{
+		creator: creator[M, A]{writer[M, A]{db, c, o.rows(norm.OpCreate)}},
+		reader:  reader[M, A]{db, r, o.mapper()},
+	}
+}
+
+func NewView[M, A any](db DB, r string, opts ...Option) View[M, A] {
+	o := newOptions(opts)
+	return View[M, A]{
+		reader: reader[M, A]{db, r, o.mapper()},
+	}
+}
+
+type a[A any] struct {
+	A A
+}
+
+type ma[M, A any] struct {
+	M M
+	A A
+}
+
+type creator[M, A any] struct {
+	writer[M, A]
+}
+
+func (c creator[M, A]) Create(ctx context.Context, args A, value M) error {
+	if err := norm.CallBeforeCreate(ctx, &args, &value); err != nil {
+		return err
+	}
+	return c.exec(ctx, args, value)
+}
+
+type updater[M, A any] struct {
+	writer[M, A]
+}
+
+func (u updater[M, A]) Update(ctx context.Context, args A, value M) error {
+	if err := norm.CallBeforeUpdate(ctx, &args, &value); err != nil {
+		return err
+	}
+	return u.exec(ctx, args, value)
+}
+
+type deleter[M, A any] struct {
+	writer[M, A]
+}
+
+func (d deleter[M, A]) Delete(ctx context.Context, args A) error {
+	if err := norm.CallBeforeDelete(ctx, &args); err != nil {
+		return err
+	}
+	var nop M
+	return d.exec(ctx, args, nop)
+}
+
+type writer[M, A any] struct {
+	db     DB
+	tpl    string
+	policy norm.RowsPolicy
+}
+
+func (w writer[M, A]) exec(ctx context.Context, args A, value M) error {
+	stmt, stmtA, err := normsql.Compile(tqla.Dollar, w.tpl, ma[M, A]{M: value, A: args})
+	if err != nil {
+		return fmt.Errorf("compile query template: %w", err)
+	}
+
+	tag, err := conn(ctx, w.db).Exec(ctx, stmt, stmtA...)
+	if err != nil {
+		return err
+	}
+
+	n := tag.RowsAffected()
+
+	norm.ReportRowsAffected(ctx, n)
+
+	return w.policy.Check(n)
+}
+
+type reader[M, A any] struct {
+	db     DB
+	tpl    string
+	mapper normsql.Mapper
+}
+
+func (r reader[M, A]) Read(ctx context.Context, args A) (value M, err error) {
+	stmt, stmtA, err := normsql.Compile(tqla.Dollar, r.tpl, a[A]{A: args})
+	if err != nil {
+		return value, fmt.Errorf("compile query template: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, stmt, stmtA...)
+
+	return r.result(ctx, rows, err)
+}
+
+func (r reader[M, A]) result(ctx context.Context, rows pgx.Rows, err error) (value M, _ error) {
+	if err != nil {
+		return value, fmt.Errorf("run query: %w", err)
+	}
+
+	err = scan(&value, rows, r.mapper)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return value, norm.ErrNotFound
+	}
+	if err != nil {
+		return value, fmt.Errorf("scan rows: %w", err)
+	}
+
+	if err := norm.CallAfterRead(ctx, &value); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+// NewPipeline makes pgx batch of the context connection, so norm.Batch sends reads in one round trip.
+func (r reader[M, A]) NewPipeline(ctx context.Context) norm.Pipeline {
+	return &pipeline{db: conn(ctx, r.db)}
+}
+
+func (r reader[M, A]) QueueRead(ctx context.Context, p norm.Pipeline, args A, set func(value M, err error)) bool {
+	pl, ok := p.(*pipeline)
+	if !ok || pl.db != conn(ctx, r.db) {
+		return false
+	}
+
+	stmt, stmtA, err := normsql.Compile(tqla.Dollar, r.tpl, a[A]{A: args})
+	if err != nil {
+		set(*new(M), fmt.Errorf("compile query template: %w", err))
+		return true
+	}
+
+	pl.batch.Queue(stmt, stmtA...)
+	pl.results = append(pl.results, func(rows pgx.Rows, err error) {
+		set(r.result(ctx, rows, err))
+	})
+
+	return true
+}
+
+type pipeline struct {
+	db      DB
+	batch   pgx.Batch
+	results []func(rows pgx.Rows, err error)
+}
+
+func (p *pipeline) Send(ctx context.Context) {
+	if len(p.results) == 0 {
+		return
+	}
+
+	br := p.db.SendBatch(ctx, &p.batch)
+	defer br.Close()
+
+	for _, result := range p.results {
+		result(br.Query())
+	}
+}
@@ -0,0 +1,147 @@
+package pgx
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+)
+
+var (
+	mapType   = reflect.TypeOf(map[string]any{})
+	bytesType = reflect.TypeOf([]byte{})
+)
+
+// scan reads rows into the value pointed by dst.
+// Slices other than []byte receive all rows, unless the result is a single array column,
+// which is scanned into the slice from the first row. Other types receive the first row:
+// structs are scanned by columns of the mapper, map[string]any by column names, the rest types from the single column.
+// It returns pgx.ErrNoRows when a single row is expected but there are none.
+func scan(dst any, rows pgx.Rows, m normsql.Mapper) error {
+	defer rows.Close()
+
+	v := reflect.ValueOf(dst).Elem()
+
+	elemType := v.Type()
+	many := elemType.Kind() == reflect.Slice && !normsql.IsScalar(elemType)
+	if many {
+		elemType = elemType.Elem()
+	}
+
+	if many && holdsArray(elemType) && isArrayColumn(rows) {
+		if rows.Next() {
+			if err := rows.Scan(dst); err != nil {
+				return err
+			}
+		}
+		rows.Close()
+		return rows.Err()
+	}
+
+	found := false
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+
+		if err := scanRow(elem, rows, m); err != nil {
+			return err
+		}
+
+		if !many {
+			v.Set(elem.Elem())
+			found = true
+			break
+		}
+
+		v.Set(reflect.Append(v, elem.Elem()))
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !many && !found {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanRow(ptr reflect.Value, rows pgx.Rows, m normsql.Mapper) error {
+	t := ptr.Type().Elem()
+
+	switch {
+	case t == mapType:
+		m, err := pgx.RowToMap(rows)
+		if err != nil {
+			return err
+		}
+		ptr.Elem().Set(reflect.ValueOf(m))
+		return nil
+
+	case t.Kind() == reflect.Pointer && isRow(t.Elem()):
+		elem := reflect.New(t.Elem())
+		if err := scanRow(elem, rows, m); err != nil {
+			return err
+		}
+		ptr.Elem().Set(elem)
+		return nil
+
+	case isRow(t):
+		return scanStruct(ptr.Elem(), rows, m)
+
+	default:
+		return rows.Scan(ptr.Interface())
+	}
+}
+
+func scanStruct(v reflect.Value, rows pgx.Rows, m normsql.Mapper) error {
+	descs := rows.FieldDescriptions()
+
+	columns := make([]string, len(descs))
+	for i, d := range descs {
+		columns[i] = d.Name
+	}
+
+	dest, err := m.Dest(v, columns)
+	if err != nil {
+		return err
+	}
+
+	return rows.Scan(dest...)
+}
+
+// holdsArray reports whether array column is scanned into slice of the elements rather than into an element.
+func holdsArray(elemType reflect.Type) bool {
+	return elemType == bytesType || elemType.Kind() != reflect.Slice && elemType.Kind() != reflect.Array
+}
+
+// isArrayColumn reports whether the result is a single column of Postgres array type.
+func isArrayColumn(rows pgx.Rows) bool {
+	descs := rows.FieldDescriptions()
+	if len(descs) != 1 {
+		return false
+	}
+
+	types := pgtype.NewMap()
+	if c := rows.Conn(); c != nil {
+		types = c.TypeMap()
+	}
+
+	t, ok := types.TypeForOID(descs[0].DataTypeOID)
+	if !ok {
+		return false
+	}
+
+	_, ok = t.Codec.(*pgtype.ArrayCodec)
+	return ok
+}
+
+// isRow reports whether values of the type are scanned from the whole row by columns names.
+func isRow(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !normsql.IsScalar(t)
+}
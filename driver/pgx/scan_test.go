@@ -0,0 +1,55 @@
+package pgx
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIsRow(t *testing.T) {
+	type model struct {
+		ID string `db:"id"`
+	}
+
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want bool
+	}{
+		{"struct", reflect.TypeOf(model{}), true},
+		{"time", reflect.TypeOf(time.Time{}), false},
+		{"scanner", reflect.TypeOf(sql.NullString{}), false},
+		{"string", reflect.TypeOf(""), false},
+		{"bytes", reflect.TypeOf([]byte{}), false},
+		{"map", reflect.TypeOf(map[string]any{}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRow(tt.typ); got != tt.want {
+				t.Errorf("isRow(%s) = %v, want %v", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldsArray(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want bool
+	}{
+		{"string", reflect.TypeOf(""), true},
+		{"bytes", reflect.TypeOf([]byte{}), true},
+		{"struct", reflect.TypeOf(struct{}{}), true},
+		{"slice", reflect.TypeOf([]string{}), false},
+		{"array", reflect.TypeOf([2]int{}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := holdsArray(tt.typ); got != tt.want {
+				t.Errorf("holdsArray(%s) = %v, want %v", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Iterator yields values one by one, Next returns io.EOF after the last value.
+type Iterator[M any] interface {
+	Next(ctx context.Context) (value M, err error)
+}
+
+// IteratorFunc is an adapter to use function as Iterator.
+type IteratorFunc[M any] func(ctx context.Context) (value M, err error)
+
+func (f IteratorFunc[M]) Next(ctx context.Context) (value M, err error) {
+	return f(ctx)
+}
+
+// SliceIterator iterates over values.
+func SliceIterator[M any](values []M) Iterator[M] {
+	i := 0
+	return IteratorFunc[M](func(ctx context.Context) (value M, err error) {
+		if i >= len(values) {
+			return value, io.EOF
+		}
+		i++
+		return values[i-1], nil
+	})
+}
+
+// Copier loads rows into the table with a dialect specific bulk API.
+// The next function returns io.EOF after the last row, written must be called with number of rows
+// after every successful write to the database.
+type Copier interface {
+	Copy(ctx context.Context, tx *sql.Tx, table string, columns []string, next func() ([]any, error), written func(rows int)) error
+}
+
+// CopyFromStdin loads rows by Postgres COPY FROM STDIN statement as lib/pq implements it.
+// The driver buffers rows until the final flush, so all of them are written at once.
+var CopyFromStdin Copier = copyFromStdin{}
+
+type copyFromStdin struct{}
+
+func (copyFromStdin) Copy(ctx context.Context, tx *sql.Tx, table string, columns []string, next func() ([]any, error), written func(rows int)) error {
+	stmt, err := tx.PrepareContext(ctx, "COPY "+quoteTable(table)+" ("+quoteColumns(columns)+") FROM STDIN")
+	if err != nil {
+		return fmt.Errorf("prepare copy: %w", err)
+	}
+	defer stmt.Close()
+
+	rows := 0
+
+	for {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return fmt.Errorf("copy row: %w", err)
+		}
+
+		rows++
+	}
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("flush copy: %w", err)
+	}
+
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	written(rows)
+
+	return nil
+}
+
+// InsertBatches loads rows by multi-row INSERT statements of up to size rows.
+// It suits databases without a bulk API supported by the database/sql driver.
+func InsertBatches(size int) Copier {
+	return insertBatches{size: size}
+}
+
+type insertBatches struct {
+	size int
+}
+
+func (b insertBatches) Copy(ctx context.Context, tx *sql.Tx, table string, columns []string, next func() ([]any, error), written func(rows int)) error {
+	if b.size <= 0 {
+		return fmt.Errorf("batch size must be positive, got %d", b.size)
+	}
+
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	prefix := "INSERT INTO " + quoteTable(table) + " (" + quoteColumns(columns) + ") VALUES "
+
+	args := make([]any, 0, b.size*len(columns))
+	rows := 0
+
+	flush := func() error {
+		if rows == 0 {
+			return nil
+		}
+
+		stmt, err := tq.placeholder.Format(prefix + strings.TrimSuffix(strings.Repeat(row+", ", rows), ", "))
+		if err != nil {
+			return fmt.Errorf("format insert: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+			return fmt.Errorf("insert rows: %w", err)
+		}
+
+		written(rows)
+
+		args = args[:0]
+		rows = 0
+
+		return nil
+	}
+
+	for {
+		values, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		args = append(args, values...)
+		rows++
+
+		if rows == b.size {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+// Bulk loads large amounts of M values into the table,
+// columns are the fields of M mapped by the column tag.
+type Bulk[M any] struct {
+	db       DB
+	table    string
+	columns  []string
+	index    [][]int
+	copier   Copier
+	progress Progress
+}
+
+// NewBulk makes loader of values into the table by CopyFromStdin unless WithCopier option is set.
+// It panics if M has fields without table column, see Mapper.WriteColumns.
+func NewBulk[M any](db DB, table string, opts ...Option) Bulk[M] {
+	o := newOptions(opts)
+
+	b := Bulk[M]{
+		db:       db,
+		table:    table,
+		copier:   o.copier,
+		progress: o.progress,
+	}
+
+	if b.copier == nil {
+		b.copier = CopyFromStdin
+	}
+
+	columns, index, err := writeColumnsOf(reflect.TypeOf((*M)(nil)).Elem(), o.scan)
+	if err != nil {
+		panic("sql: NewBulk: " + err.Error())
+	}
+
+	b.columns, b.index = columns, index
+
+	return b
+}
+
+// Columns returns loaded columns in order of M fields.
+func (b Bulk[M]) Columns() []string {
+	return b.columns
+}
+
+// Load loads all values of the iterator and returns number of loaded values.
+// It runs in the context transaction, otherwise in its own one, so values are loaded completely or not at all.
+func (b Bulk[M]) Load(ctx context.Context, values Iterator[M]) (_ int64, err error) {
+	if len(b.columns) == 0 {
+		return 0, fmt.Errorf("no columns to load")
+	}
+
+	tx := txValue(ctx)
+	if tx == nil {
+		tx, err = b.db.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+	}
+
+	next := func() ([]any, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		value, err := values.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		v := reflect.ValueOf(value)
+		row := make([]any, len(b.index))
+		for i, index := range b.index {
+			row[i] = v.FieldByIndex(index).Interface()
+		}
+
+		return row, nil
+	}
+
+	p := b.progress
+
+	written := func(rows int) {
+		p.Add(int64(rows))
+	}
+
+	if err := b.copier.Copy(ctx, tx, b.table, b.columns, next, written); err != nil {
+		return 0, err
+	}
+
+	if txValue(ctx) == nil {
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("commit transaction: %w", err)
+		}
+	}
+
+	return p.Done(), nil
+}
+
+// Progress counts values written by Bulk loaders and reports them every time the count passes a multiple of Every,
+// so drivers share the reporting of WithProgress.
+type Progress struct {
+	Every int64
+	Fn    func(n int64)
+
+	n        int64
+	reported bool
+}
+
+// Add counts rows written to the database.
+func (p *Progress) Add(rows int64) {
+	if rows == 0 {
+		return
+	}
+
+	prev := p.n
+	p.n += rows
+	p.reported = false
+
+	if p.Fn != nil && p.Every > 0 && p.n/p.Every > prev/p.Every {
+		p.Fn(p.n)
+		p.reported = true
+	}
+}
+
+// Done reports the final count unless it's reported already and returns it.
+func (p *Progress) Done() int64 {
+	if p.Fn != nil && !p.reported {
+		p.Fn(p.n)
+		p.reported = true
+	}
+	return p.n
+}
+
+func quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, p := range parts {
+		parts[i] = tq.quote(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = tq.quote(c)
+	}
+	return strings.Join(quoted, ", ")
+}
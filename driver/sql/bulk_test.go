@@ -0,0 +1,65 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestSliceIterator(t *testing.T) {
+	it := SliceIterator([]int{1, 2})
+
+	var got []int
+	for {
+		v, err := it.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+	}
+
+	if want := []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SliceIterator() = %v, want %v", got, want)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	tests := []struct {
+		name  string
+		every int64
+		rows  []int64
+		want  []int64
+	}{
+		{"every", 2, []int64{1, 1, 1, 1, 1}, []int64{2, 4, 5}},
+		{"exact end", 2, []int64{1, 1, 1, 1}, []int64{2, 4}},
+		{"only end", 0, []int64{1, 1, 1}, []int64{3}},
+		{"batches", 10, []int64{25, 25, 5}, []int64{25, 50, 55}},
+		{"small batches", 10, []int64{4, 4, 4}, []int64{12}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			p := Progress{Every: tt.every, Fn: func(n int64) { got = append(got, n) }}
+
+			for _, rows := range tt.rows {
+				p.Add(rows)
+			}
+			p.Done()
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("progress calls = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteTable(t *testing.T) {
+	if got, want := quoteTable(`public.my"table`), `"public"."my""table"`; got != want {
+		t.Errorf("quoteTable() = %s, want %s", got, want)
+	}
+}
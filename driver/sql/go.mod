@@ -4,10 +4,6 @@ go 1.19
 
 require (
 	github.com/VauntDev/tqla v0.0.1
-	github.com/WinPooh32/norm v0.1.1
-)
-
-require (
-	github.com/blockloop/scan/v2 v2.5.0
-	golang.org/x/text v0.12.0 // indirect
+	github.com/WinPooh32/norm v0.1.2-0.20261018231856-3db48776d691
+	github.com/mattn/go-sqlite3 v1.14.17
 )
@@ -0,0 +1,305 @@
+package sql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type converter func(src any) (any, error)
+
+// Mapper maps query result columns to struct fields the same way as objects of the package do,
+// so other drivers can share the mapping.
+type Mapper struct {
+	o scanOptions
+}
+
+// NewMapper makes mapper configured by WithColumnTag, WithSnakeCase and WithLenientColumns options,
+// other options are ignored. It panics on WithConverter, as converters depend on values of the database/sql driver.
+func NewMapper(opts ...Option) Mapper {
+	o := newOptions(opts).scan
+	if o.converters != nil {
+		panic("sql: NewMapper with converters")
+	}
+	return Mapper{o}
+}
+
+// Fields returns indexes of the struct type fields by their column names.
+func (m Mapper) Fields(t reflect.Type) map[string][]int {
+	fields := m.o.fields(t)
+
+	index := make(map[string][]int, len(fields))
+	for col, f := range fields {
+		index[col] = f.index
+	}
+
+	return index
+}
+
+// Columns returns columns of the struct type fields ordered by their declaration and indexes of the fields.
+func (m Mapper) Columns(t reflect.Type) (columns []string, index [][]int) {
+	return columnsOf(t, m.o)
+}
+
+// Dest returns pointers to fields of the struct v receiving the columns in their order.
+// A column without field fails unless WithLenientColumns is set, then it's scanned into a discarded value.
+func (m Mapper) Dest(v reflect.Value, columns []string) ([]any, error) {
+	fields := m.o.fields(v.Type())
+	dest := make([]any, len(columns))
+
+	for i, col := range columns {
+		f, ok := fields[col]
+		switch {
+		case ok:
+			dest[i] = v.FieldByIndex(f.index).Addr().Interface()
+		case m.o.lenient:
+			dest[i] = new(any)
+		default:
+			return nil, fmt.Errorf("column %q has no matching field in %s", col, v.Type())
+		}
+	}
+
+	return dest, nil
+}
+
+// WriteColumns is Columns of table writes, e.g. bulk loads. It fails on fields of nested structs,
+// which have no table column, and on fields of converted types, which are converted by reads only.
+func (m Mapper) WriteColumns(t reflect.Type) (columns []string, index [][]int, err error) {
+	return writeColumnsOf(t, m.o)
+}
+
+type scanOptions struct {
+	tag        string
+	lenient    bool
+	snakeCase  bool
+	converters map[reflect.Type]converter
+}
+
+type rowsScanner interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+// field is a struct field which receives column value.
+type field struct {
+	index  []int
+	conv   converter
+	nested bool
+}
+
+// fields maps column names to struct fields.
+// Columns of nested structs are prefixed by the nested struct column name and dot, e.g. "author.name".
+func (o scanOptions) fields(t reflect.Type) map[string]field {
+	m := map[string]field{}
+	o.collectFields(t, "", nil, m)
+	return m
+}
+
+func (o scanOptions) collectFields(t reflect.Type, prefix string, index []int, m map[string]field) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		name, tagged := f.Tag.Lookup(o.tag)
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(index[:len(index):len(index)], i)
+		conv := o.converters[f.Type]
+		isColumn := conv != nil || IsScalar(f.Type)
+
+		switch {
+		case !isColumn && f.Type.Kind() == reflect.Struct:
+			switch {
+			case tagged && name != "":
+				o.collectFields(f.Type, prefix+name+".", fieldIndex, m)
+			case o.snakeCase && !f.Anonymous:
+				o.collectFields(f.Type, prefix+SnakeCase(f.Name)+".", fieldIndex, m)
+			default:
+				o.collectFields(f.Type, prefix, fieldIndex, m)
+			}
+			continue
+
+		case !f.IsExported():
+			continue
+
+		case !tagged || name == "":
+			if !o.snakeCase {
+				continue
+			}
+			name = SnakeCase(f.Name)
+		}
+
+		if _, ok := m[prefix+name]; ok {
+			continue
+		}
+
+		m[prefix+name] = field{index: fieldIndex, conv: conv, nested: prefix != ""}
+	}
+}
+
+// scanStructs scans rows into slice of structs or pointers to structs.
+// When one is set only the first row is scanned.
+func (o scanOptions) scanStructs(slice reflect.Value, rows rowsScanner, one bool) error {
+	defer rows.Close()
+
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("can't scan columns into %s", elemType)
+	}
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	fields := o.fields(structType)
+	colFields := make([]*field, len(cols))
+
+	for i, col := range cols {
+		f, ok := fields[col]
+		if !ok {
+			if !o.lenient {
+				return fmt.Errorf("column %q has no matching field in %s", col, structType)
+			}
+			continue
+		}
+		colFields[i] = &f
+	}
+
+	ptrs := make([]any, len(cols))
+	raws := make([]any, len(cols))
+
+	for rows.Next() {
+		item := reflect.New(structType).Elem()
+
+		for i, f := range colFields {
+			switch {
+			case f == nil:
+				ptrs[i] = new(any)
+			case f.conv != nil:
+				raws[i] = nil
+				ptrs[i] = &raws[i]
+			default:
+				ptrs[i] = item.FieldByIndex(f.index).Addr().Interface()
+			}
+		}
+
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+
+		for i, f := range colFields {
+			if f == nil || f.conv == nil {
+				continue
+			}
+
+			v, err := f.conv(raws[i])
+			if err != nil {
+				return fmt.Errorf("convert column %q: %w", cols[i], err)
+			}
+
+			item.FieldByIndex(f.index).Set(reflect.ValueOf(v))
+		}
+
+		if elemType.Kind() == reflect.Pointer {
+			item = item.Addr()
+		}
+
+		slice.Set(reflect.Append(slice, item))
+
+		if one {
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+// SnakeCase converts Go identifier to snake_case, e.g. UserID to "user_id".
+// It's the column name of untagged fields mapped by WithSnakeCase.
+func SnakeCase(s string) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// columnsOf returns columns of the struct fields ordered by their declaration.
+func columnsOf(t reflect.Type, o scanOptions) (columns []string, index [][]int) {
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	fields := o.fields(t)
+
+	for col := range fields {
+		columns = append(columns, col)
+	}
+
+	sort.Slice(columns, func(i, j int) bool {
+		a, b := fields[columns[i]].index, fields[columns[j]].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	for _, col := range columns {
+		index = append(index, fields[col].index)
+	}
+
+	return columns, index
+}
+
+func writeColumnsOf(t reflect.Type, o scanOptions) (columns []string, index [][]int, err error) {
+	columns, index = columnsOf(t, o)
+	if len(columns) == 0 {
+		return nil, nil, nil
+	}
+
+	fields := o.fields(t)
+
+	for _, col := range columns {
+		switch f := fields[col]; {
+		case f.nested:
+			return nil, nil, fmt.Errorf("column %q is a field of nested struct in %s", col, t)
+		case f.conv != nil:
+			return nil, nil, fmt.Errorf("column %q has a read converter in %s", col, t)
+		}
+	}
+
+	return columns, index, nil
+}
@@ -0,0 +1,317 @@
+package sql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeRows struct {
+	cols []string
+	rows [][]any
+	i    int
+}
+
+func (r *fakeRows) Columns() ([]string, error) { return r.cols, nil }
+
+func (r *fakeRows) Next() bool {
+	r.i++
+	return r.i <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	row := r.rows[r.i-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("expected %d destination arguments in Scan, not %d", len(row), len(dest))
+	}
+	for i, d := range dest {
+		v := reflect.ValueOf(d).Elem()
+		if row[i] == nil {
+			v.Set(reflect.Zero(v.Type()))
+			continue
+		}
+		v.Set(reflect.ValueOf(row[i]))
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error { return nil }
+
+func (r *fakeRows) Close() error { return nil }
+
+type author struct {
+	Name string `db:"name"`
+}
+
+type meta struct {
+	Tags []string `json:"tags"`
+}
+
+type base struct {
+	ID string `db:"id"`
+}
+
+type post struct {
+	base
+	Title     string `db:"title"`
+	Author    author `db:"author"`
+	Meta      meta   `db:"meta"`
+	Skipped   string `db:"-"`
+	CreatedBy string
+}
+
+func TestScanOptions_scanStructs(t *testing.T) {
+	jsonMeta := WithConverter(FromJSON[meta])
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		cols    []string
+		rows    [][]any
+		want    []post
+		wantErr string
+	}{
+		{
+			name: "tags",
+			cols: []string{"id", "title", "author.name"},
+			rows: [][]any{{"1", "t1", "a1"}, {"2", "t2", "a2"}},
+			want: []post{
+				{base: base{ID: "1"}, Title: "t1", Author: author{Name: "a1"}},
+				{base: base{ID: "2"}, Title: "t2", Author: author{Name: "a2"}},
+			},
+		},
+		{
+			name: "lenient",
+			opts: []Option{WithLenientColumns()},
+			cols: []string{"id", "unknown", "-", "created_by"},
+			rows: [][]any{{"1", "x", "y", "z"}},
+			want: []post{{base: base{ID: "1"}}},
+		},
+		{
+			name:    "strict",
+			cols:    []string{"id", "unknown"},
+			rows:    [][]any{{"1", "x"}},
+			wantErr: `column "unknown" has no matching field`,
+		},
+		{
+			name: "snake case",
+			opts: []Option{WithSnakeCase()},
+			cols: []string{"id", "created_by"},
+			rows: [][]any{{"1", "me"}},
+			want: []post{{base: base{ID: "1"}, CreatedBy: "me"}},
+		},
+		{
+			name: "converter",
+			opts: []Option{jsonMeta},
+			cols: []string{"id", "meta"},
+			rows: [][]any{{"1", []byte(`{"tags":["a","b"]}`)}, {"2", nil}},
+			want: []post{
+				{base: base{ID: "1"}, Meta: meta{Tags: []string{"a", "b"}}},
+				{base: base{ID: "2"}},
+			},
+		},
+		{
+			name:    "converter error",
+			opts:    []Option{jsonMeta},
+			cols:    []string{"meta"},
+			rows:    [][]any{{[]byte(`{`)}},
+			wantErr: `convert column "meta"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []post
+
+			o := newOptions(tt.opts).scan
+			err := o.scanStructs(reflect.ValueOf(&got).Elem(), &fakeRows{cols: tt.cols, rows: tt.rows}, false)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("scanStructs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanStructs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanOptions_scanStructs_Tag(t *testing.T) {
+	type model struct {
+		ID string `sql:"id"`
+	}
+
+	var got []*model
+
+	o := newOptions([]Option{WithColumnTag("sql")}).scan
+
+	err := o.scanStructs(reflect.ValueOf(&got).Elem(), &fakeRows{
+		cols: []string{"id"},
+		rows: [][]any{{"1"}, {"2"}},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("scanStructs() = %v, want the first row only", got)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"ID":         "id",
+		"Name":       "name",
+		"CreatedAt":  "created_at",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"A1":         "a1",
+	}
+	for in, want := range tests {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestColumnsOf(t *testing.T) {
+	type meta struct {
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	type model struct {
+		ID   string `db:"id"`
+		Name string `db:"name"`
+		Skip string `db:"-"`
+		meta
+		Age int `db:"age"`
+	}
+
+	columns, index := columnsOf(reflect.TypeOf(model{}), scanOptions{tag: "db"})
+
+	if want := []string{"id", "name", "created_at", "age"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("columnsOf() columns = %v, want %v", columns, want)
+	}
+
+	if want := [][]int{{0}, {1}, {3, 0}, {4}}; !reflect.DeepEqual(index, want) {
+		t.Errorf("columnsOf() index = %v, want %v", index, want)
+	}
+}
+
+func TestWriteColumnsOf(t *testing.T) {
+	type meta struct {
+		Tags []string
+	}
+	type author struct {
+		Name string `db:"name"`
+	}
+
+	tests := []struct {
+		name    string
+		t       reflect.Type
+		opts    []Option
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "flat",
+			t: reflect.TypeOf(struct {
+				ID string `db:"id"`
+				author
+			}{}),
+			want: []string{"id", "name"},
+		},
+		{
+			name: "nested",
+			t: reflect.TypeOf(struct {
+				ID     string `db:"id"`
+				Author author `db:"author"`
+			}{}),
+			wantErr: `column "author.name" is a field of nested struct`,
+		},
+		{
+			name: "converted",
+			t: reflect.TypeOf(struct {
+				Meta meta `db:"meta"`
+			}{}),
+			opts:    []Option{WithConverter(FromJSON[meta])},
+			wantErr: `column "meta" has a read converter`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, _, err := writeColumnsOf(tt.t, newOptions(tt.opts).scan)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("writeColumnsOf() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(columns, tt.want) {
+				t.Errorf("writeColumnsOf() = %v, want %v", columns, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper(t *testing.T) {
+	type author struct {
+		Name string
+	}
+	type post struct {
+		PostID string `json:"id"`
+		Author author
+	}
+
+	m := NewMapper(WithColumnTag("json"), WithSnakeCase())
+
+	want := map[string][]int{"id": {0}, "author.name": {1, 0}}
+	if got := m.Fields(reflect.TypeOf(post{})); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+
+	columns, _ := m.Columns(reflect.TypeOf(post{}))
+	if want := []string{"id", "author.name"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("Columns() = %v, want %v", columns, want)
+	}
+
+	var p post
+
+	dest, err := m.Dest(reflect.ValueOf(&p).Elem(), []string{"author.name", "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []any{&p.Author.Name, &p.PostID}; !reflect.DeepEqual(dest, want) {
+		t.Errorf("Dest() = %v, want %v", dest, want)
+	}
+
+	if _, err := m.Dest(reflect.ValueOf(&p).Elem(), []string{"id", "unknown"}); err == nil {
+		t.Error("Dest() of unknown column: expected error")
+	}
+
+	lenient := NewMapper(WithColumnTag("json"), WithLenientColumns())
+	if dest, err := lenient.Dest(reflect.ValueOf(&p).Elem(), []string{"id", "unknown"}); err != nil || len(dest) != 2 {
+		t.Errorf("Dest() of lenient mapper = %v, %v", dest, err)
+	}
+}
+
+func TestNewMapper_Panic_Converter(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	NewMapper(WithConverter(FromJSON[meta]))
+}
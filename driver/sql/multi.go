@@ -0,0 +1,164 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/WinPooh32/norm"
+)
+
+// MultiView reads successive result sets of a single query into fields of T,
+// e.g. a stored procedure returning an order header and its lines:
+//
+//	type Order struct {
+//		Header OrderHeader
+//		Lines  []OrderLine
+//	}
+//
+// Exported fields of T receive result sets in declaration order, fields tagged `db:"-"` are skipped.
+// Every field is scanned the same way as the View of its type does, so an empty result set
+// of a non-slice field fails with norm.ErrNotFound.
+//
+// The query is not prepared, because most drivers refuse to prepare several statements.
+// Note that lib/pq returns several result sets only for queries without parameters.
+type MultiView[T, A any] struct {
+	db      DB
+	tpl     string
+	sets    []resultSet
+	scan    scanOptions
+	scope   scopeOptions
+	timeout opTimeout
+	err     error
+}
+
+type resultSet struct {
+	field reflect.StructField
+	mode  scanMode
+}
+
+func NewMultiView[T, A any](db DB, r string, opts ...Option) MultiView[T, A] {
+	o := newOptions(opts)
+	sets, err := resultSetsOf(reflect.TypeOf((*T)(nil)).Elem(), o.scan.tag)
+	return MultiView[T, A]{
+		db:      db,
+		tpl:     r,
+		sets:    sets,
+		scan:    o.scan,
+		scope:   o.scope,
+		timeout: o.timeout(norm.OpRead),
+		err:     err,
+	}
+}
+
+func (v MultiView[T, A]) Read(ctx context.Context, args A) (value T, err error) {
+	err = v.timeout.run(ctx, func(ctx context.Context) error {
+		value, err = v.read(ctx, args)
+		return err
+	})
+	return value, err
+}
+
+func (v MultiView[T, A]) read(ctx context.Context, args A) (value T, err error) {
+	if v.err != nil {
+		return value, v.err
+	}
+
+	q := newQueryer(txValue(ctx), readDB(ctx, v.db))
+
+	c, err := v.scope.bind(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	stmtRaw, stmtA, err := tq.Compile(v.tpl, a[A]{A: args, C: c})
+	if err != nil {
+		return value, fmt.Errorf("compile query template: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, stmtRaw, stmtA...)
+	if err != nil {
+		return value, fmt.Errorf("run query: %w", err)
+	}
+	defer rows.Close()
+
+	dst := reflect.ValueOf(&value).Elem()
+
+	for i, set := range v.sets {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return value, fmt.Errorf("read result set %d: %w", i+1, err)
+			}
+			return value, fmt.Errorf("query returned %d result sets, want %d", i, len(v.sets))
+		}
+
+		ptr := dst.FieldByIndex(set.field.Index).Addr()
+
+		if err := scanValue(set.mode, v.scan, ptr, currentSet{rows}); err != nil {
+			return value, fmt.Errorf("result set %d (%s): %w", i+1, set.field.Name, err)
+		}
+	}
+
+	if err := rows.Close(); err != nil {
+		return value, err
+	}
+
+	if err := norm.CallAfterRead(ctx, &value); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+// Validate checks that templates are parsed and refer to existing fields of A and T is a struct.
+// There is no ValidateDB, since multi-statement queries can't be prepared.
+func (v MultiView[T, A]) Validate() error {
+	if v.err != nil {
+		return v.err
+	}
+	return validate(checkOf[a[A]]("read", v.tpl))
+}
+
+func resultSetsOf(t reflect.Type, tag string) ([]resultSet, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("multi view expects struct of result sets, got %s", t)
+	}
+
+	var sets []resultSet
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get(tag) == "-" {
+			continue
+		}
+		sets = append(sets, resultSet{field: f, mode: scanModeFor(f.Type)})
+	}
+
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("multi view expects struct of result sets, %s has no exported fields", t)
+	}
+
+	return sets, nil
+}
+
+// currentSet limits scanning to the current result set: Close is left to the caller,
+// so the following result sets remain available.
+type currentSet struct {
+	*sql.Rows
+}
+
+func (currentSet) Close() error {
+	return nil
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func newQueryer(tx *sql.Tx, db DB) queryer {
+	if tx != nil {
+		return tx
+	}
+	return db
+}
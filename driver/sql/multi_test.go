@@ -0,0 +1,59 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResultSetsOf(t *testing.T) {
+	type model struct {
+		ID string `db:"id"`
+	}
+
+	type order struct {
+		Header  model
+		Lines   []model
+		Total   int64
+		Skipped string `db:"-"`
+		hidden  string
+	}
+
+	sets, err := resultSetsOf(reflect.TypeOf(order{}), "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	var modes []scanMode
+	for _, s := range sets {
+		got = append(got, s.field.Name)
+		modes = append(modes, s.mode)
+	}
+
+	if want := []string{"Header", "Lines", "Total"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("resultSetsOf() fields = %v, want %v", got, want)
+	}
+
+	if want := []scanMode{scanRow, scanRows, scanScalar}; !reflect.DeepEqual(modes, want) {
+		t.Errorf("resultSetsOf() modes = %v, want %v", modes, want)
+	}
+}
+
+func TestResultSetsOf_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     reflect.Type
+		wantErr string
+	}{
+		{"not struct", reflect.TypeOf([]int{}), "multi view expects struct of result sets, got []int"},
+		{"no fields", reflect.TypeOf(struct{ a int }{}), "multi view expects struct of result sets, struct { a int } has no exported fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resultSetsOf(tt.typ, "db")
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("resultSetsOf() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/WinPooh32/norm"
+)
+
+// Option configures objects made by the package constructors.
+type Option func(*options)
+
+type options struct {
+	scan     scanOptions
+	affected map[norm.Op]norm.RowsPolicy
+	copier   Copier
+	progress Progress
+	scope    scopeOptions
+	timeouts map[norm.Op]time.Duration
+	stmtTime bool
+	flag     bool
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		scan: scanOptions{
+			tag: "db",
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLenientColumns makes reads ignore columns without matching struct field.
+// By default such columns fail the read.
+func WithLenientColumns() Option {
+	return func(o *options) {
+		o.scan.lenient = true
+	}
+}
+
+// WithColumnTag sets struct tag which holds column names, "db" by default.
+func WithColumnTag(tag string) Option {
+	return func(o *options) {
+		o.scan.tag = tag
+	}
+}
+
+// WithSnakeCase maps untagged struct fields to columns by their snake_case names,
+// e.g. CreatedAt to "created_at". Untagged nested structs get their snake_case name as prefix.
+func WithSnakeCase() Option {
+	return func(o *options) {
+		o.scan.snakeCase = true
+	}
+}
+
+// WithAffectedRows sets policy of rows affected by write operations ops, or by all writes when ops are omitted.
+// By default a write must affect at least one row, otherwise it fails with norm.ErrNotAffected.
+func WithAffectedRows(p norm.RowsPolicy, ops ...norm.Op) Option {
+	return func(o *options) {
+		if o.affected == nil {
+			o.affected = map[norm.Op]norm.RowsPolicy{}
+		}
+		if len(ops) == 0 {
+			ops = []norm.Op{norm.OpCreate, norm.OpUpdate, norm.OpDelete, norm.OpRestore}
+		}
+		for _, op := range ops {
+			o.affected[op] = p
+		}
+	}
+}
+
+func (o options) rows(op norm.Op) norm.RowsPolicy {
+	if p, ok := o.affected[op]; ok {
+		return p
+	}
+	return norm.AtLeast(1)
+}
+
+// WithRequiredScope makes objects fail with ErrMissingScope before running queries
+// when any of the keys is missing in the context scope set by WithScope.
+func WithRequiredScope(keys ...string) Option {
+	return func(o *options) {
+		o.scope.required = append(o.scope.required, keys...)
+	}
+}
+
+// WithLocalSetting makes objects set the Postgres run-time parameter name to the scope value of key
+// by set_config(name, value, true) before queries within transactions, the same as SET LOCAL does.
+// It's intended for row-level security policies, e.g.:
+//
+//	WithLocalSetting("app.tenant", "tenant")
+//
+// lets the policy compare rows with current_setting('app.tenant'). Queries outside transactions are not affected,
+// within transactions they fail with ErrMissingScope when the context has no value of key.
+func WithLocalSetting(name, key string) Option {
+	return func(o *options) {
+		o.scope.settings = append(o.scope.settings, localSetting{name: name, key: key})
+	}
+}
+
+// WithTimeout sets default timeout of operations ops, or of all operations when ops are omitted.
+// It applies only when the context has no deadline or a later one.
+// Exceeded deadlines are reported as *norm.TimeoutError matching norm.ErrTimeout.
+func WithTimeout(d time.Duration, ops ...norm.Op) Option {
+	return func(o *options) {
+		if o.timeouts == nil {
+			o.timeouts = map[norm.Op]time.Duration{}
+		}
+		if len(ops) == 0 {
+			ops = []norm.Op{norm.OpCreate, norm.OpRead, norm.OpUpdate, norm.OpDelete, norm.OpRestore}
+		}
+		for _, op := range ops {
+			o.timeouts[op] = d
+		}
+	}
+}
+
+// WithStatementTimeout makes operations with timeout set Postgres statement_timeout to it
+// by set_config('statement_timeout', ms, true) within transactions, the same as SET LOCAL does,
+// so the server aborts slow statements too. The setting lasts until the end of the transaction.
+func WithStatementTimeout() Option {
+	return func(o *options) {
+		o.stmtTime = true
+	}
+}
+
+func (o options) timeout(op norm.Op) opTimeout {
+	return opTimeout{op: op, timeout: o.timeouts[op], statement: o.stmtTime}
+}
+
+// WithExistsFlag makes Exister read the result from the single column of the first row,
+// e.g. of SELECT EXISTS(...). Boolean, integer 0 or 1 and textual values are accepted.
+// By default any row means existence.
+func WithExistsFlag() Option {
+	return func(o *options) {
+		o.flag = true
+	}
+}
+
+// WithCopier sets bulk API used by Bulk loader.
+func WithCopier(c Copier) Option {
+	return func(o *options) {
+		o.copier = c
+	}
+}
+
+// WithProgress makes Bulk loader call fn with number of written values every time it passes a multiple of every
+// and at the end of load. Copiers report whole batches once they are written, CopyFromStdin reports all rows at the end.
+func WithProgress(every int64, fn func(n int64)) Option {
+	return func(o *options) {
+		o.progress = Progress{Every: every, Fn: fn}
+	}
+}
+
+// WithConverter makes reads convert scanned column values to fields of type T by conv.
+// The src is a value returned by the database driver.
+func WithConverter[T any](conv func(src any) (T, error)) Option {
+	return func(o *options) {
+		if o.scan.converters == nil {
+			o.scan.converters = map[reflect.Type]converter{}
+		}
+		o.scan.converters[reflect.TypeOf((*T)(nil)).Elem()] = func(src any) (any, error) {
+			return conv(src)
+		}
+	}
+}
+
+// FromJSON is a converter of JSON columns, for example:
+//
+//	WithConverter(FromJSON[Meta])
+func FromJSON[T any](src any) (value T, err error) {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		return value, nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return value, fmt.Errorf("unsupported JSON source type %T", src)
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, fmt.Errorf("unmarshal JSON: %w", err)
+	}
+
+	return value, nil
+}
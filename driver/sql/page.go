@@ -0,0 +1,291 @@
+package sql
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/WinPooh32/norm"
+)
+
+// OffsetPager reads pages using LIMIT/OFFSET.
+// The template gets page parameters as {{ .P.Limit }} and {{ .P.Offset }}.
+type OffsetPager[T, A any] struct {
+	db      DB
+	tpl     string
+	cursor  cursorCodec
+	scan    scanOptions
+	scope   scopeOptions
+	timeout opTimeout
+}
+
+// NewOffsetPager makes offset pager. The secret signs page cursors, it panics when the secret is shorter
+// than 16 bytes. Cursors are bound to the args of the page, so they can't be reused with other args.
+func NewOffsetPager[T, A any](db DB, secret []byte, r string, opts ...Option) OffsetPager[T, A] {
+	o := newOptions(opts)
+	return OffsetPager[T, A]{
+		db:      db,
+		tpl:     r,
+		cursor:  newCursorCodec(secret, "offset"),
+		scan:    o.scan,
+		scope:   o.scope,
+		timeout: o.timeout(norm.OpRead),
+	}
+}
+
+func (p OffsetPager[T, A]) Page(ctx context.Context, args A, req norm.PageRequest) (page norm.Page[T], err error) {
+	if req.Limit <= 0 {
+		return page, fmt.Errorf("page limit must be positive: %d", req.Limit)
+	}
+
+	var offset int
+
+	if req.Cursor != "" {
+		if err := p.cursor.decode(req.Cursor, args, &offset); err != nil {
+			return page, err
+		}
+	}
+
+	c, err := p.scope.bind(ctx)
+	if err != nil {
+		return page, err
+	}
+
+	items, err := readPage[T](ctx, p.db, p.tpl, p.scan, p.timeout, pa[A, struct{}]{
+		A: args,
+		C: c,
+		P: pageParams[struct{}]{
+			Limit:  req.Limit + 1,
+			Offset: offset,
+		},
+	})
+	if err != nil {
+		return page, err
+	}
+
+	page = cutPage(items, req.Limit)
+
+	if page.HasNext {
+		page.Next, err = p.cursor.encode(args, offset+req.Limit)
+		if err != nil {
+			return page, err
+		}
+	}
+
+	return page, nil
+}
+
+func (p OffsetPager[T, A]) Validate() error {
+	return validate(checkOf[pa[A, struct{}]]("page", p.tpl))
+}
+
+func (p OffsetPager[T, A]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, p.db, checkOf[pa[A, struct{}]]("page", p.tpl))
+}
+
+// KeysetPager reads pages seeking after the key of the last read item.
+// The template gets page parameters as {{ .P.Limit }}, {{ .P.After }} and {{ .P.HasAfter }},
+// where After is the key of the previous page last item, e.g.:
+//
+//	WHERE {{ if .P.HasAfter }} "id" > {{ .P.After }} {{ else }} TRUE {{ end }}
+//	ORDER BY "id" ASC
+//	LIMIT {{ .P.Limit }}
+type KeysetPager[T norm.Keyer[K], A any, K comparable] struct {
+	db      DB
+	tpl     string
+	cursor  cursorCodec
+	scan    scanOptions
+	scope   scopeOptions
+	timeout opTimeout
+}
+
+// NewKeysetPager makes keyset pager. The secret signs page cursors, it panics when the secret is shorter
+// than 16 bytes. Cursors are bound to the args of the page, so they can't be reused with other args.
+func NewKeysetPager[T norm.Keyer[K], A any, K comparable](db DB, secret []byte, r string, opts ...Option) KeysetPager[T, A, K] {
+	o := newOptions(opts)
+	return KeysetPager[T, A, K]{
+		db:      db,
+		tpl:     r,
+		cursor:  newCursorCodec(secret, "keyset"),
+		scan:    o.scan,
+		scope:   o.scope,
+		timeout: o.timeout(norm.OpRead),
+	}
+}
+
+func (p KeysetPager[T, A, K]) Page(ctx context.Context, args A, req norm.PageRequest) (page norm.Page[T], err error) {
+	if req.Limit <= 0 {
+		return page, fmt.Errorf("page limit must be positive: %d", req.Limit)
+	}
+
+	params := pageParams[K]{
+		Limit: req.Limit + 1,
+	}
+
+	if req.Cursor != "" {
+		if err := p.cursor.decode(req.Cursor, args, &params.After); err != nil {
+			return page, err
+		}
+		params.HasAfter = true
+	}
+
+	c, err := p.scope.bind(ctx)
+	if err != nil {
+		return page, err
+	}
+
+	items, err := readPage[T](ctx, p.db, p.tpl, p.scan, p.timeout, pa[A, K]{A: args, P: params, C: c})
+	if err != nil {
+		return page, err
+	}
+
+	page = cutPage(items, req.Limit)
+
+	if page.HasNext {
+		page.Next, err = p.cursor.encode(args, items[req.Limit-1].Key())
+		if err != nil {
+			return page, err
+		}
+	}
+
+	return page, nil
+}
+
+func (p KeysetPager[T, A, K]) Validate() error {
+	return validate(checkOf[pa[A, K]]("page", p.tpl))
+}
+
+func (p KeysetPager[T, A, K]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, p.db, checkOf[pa[A, K]]("page", p.tpl))
+}
+
+type pageParams[K any] struct {
+	Limit    int
+	Offset   int
+	After    K
+	HasAfter bool
+}
+
+type pa[A, K any] struct {
+	A A
+	P pageParams[K]
+	C Scope
+}
+
+func readPage[T any](ctx context.Context, db DB, tpl string, o scanOptions, t opTimeout, data any) (items []T, err error) {
+	err = t.run(ctx, func(ctx context.Context) error {
+		pr := newPreparer(txValue(ctx), readDB(ctx, db))
+
+		rows, err := query(ctx, pr, tpl, data)
+		if err != nil {
+			return err
+		}
+
+		return scanInto(scanModeOf[[]T](), o, &items, rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := norm.CallAfterRead(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func cutPage[T any](items []T, limit int) (page norm.Page[T]) {
+	if len(items) > limit {
+		return norm.Page[T]{Items: items[:limit], HasNext: true}
+	}
+	return norm.Page[T]{Items: items}
+}
+
+// minSecretLen is the minimal length of cursor secrets.
+const minSecretLen = 16
+
+// cursorCodec makes opaque cursors signed with HMAC-SHA256,
+// so clients can't forge them. Signatures cover digest of the page args.
+type cursorCodec struct {
+	secret []byte
+	kind   string
+}
+
+func newCursorCodec(secret []byte, kind string) cursorCodec {
+	if len(secret) < minSecretLen {
+		panic(fmt.Sprintf("sql: cursor secret must be at least %d bytes, got %d", minSecretLen, len(secret)))
+	}
+	return cursorCodec{secret: secret, kind: kind}
+}
+
+func (c cursorCodec) encode(args, v any) (string, error) {
+	digest, err := argsDigest(args)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshal cursor: %w", err)
+	}
+
+	enc := base64.RawURLEncoding
+
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(c.sign(digest, payload)), nil
+}
+
+func (c cursorCodec) decode(cursor string, args, v any) error {
+	enc := base64.RawURLEncoding
+
+	rawPayload, rawSig, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return norm.ErrBadCursor
+	}
+
+	payload, err := enc.DecodeString(rawPayload)
+	if err != nil {
+		return fmt.Errorf("%w: %s", norm.ErrBadCursor, err)
+	}
+
+	sig, err := enc.DecodeString(rawSig)
+	if err != nil {
+		return fmt.Errorf("%w: %s", norm.ErrBadCursor, err)
+	}
+
+	digest, err := argsDigest(args)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal(sig, c.sign(digest, payload)) {
+		return fmt.Errorf("%w: signature mismatch", norm.ErrBadCursor)
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w: %s", norm.ErrBadCursor, err)
+	}
+
+	return nil
+}
+
+func (c cursorCodec) sign(digest, payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(c.kind))
+	mac.Write(digest)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// argsDigest returns SHA-256 of the JSON encoded args.
+func argsDigest(args any) ([]byte, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("marshal cursor args: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
@@ -0,0 +1,105 @@
+package sql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/WinPooh32/norm"
+)
+
+var testSecret = []byte("test secret of 32 bytes length!!")
+
+func TestCursorCodec(t *testing.T) {
+	type key struct {
+		ID    string
+		Order int
+	}
+
+	c := newCursorCodec(testSecret, "keyset")
+
+	want := key{ID: "id01", Order: 10}
+
+	cursor, err := c.encode("args", want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got key
+
+	if err := c.decode(cursor, "args", &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != want {
+		t.Errorf("decode() = %v, want %v", got, want)
+	}
+}
+
+func TestCursorCodec_Error_BadCursor(t *testing.T) {
+	c := newCursorCodec(testSecret, "offset")
+
+	valid, err := c.encode("args", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forged, err := newCursorCodec([]byte("other secret of 32 bytes length!"), "offset").encode("args", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKind, err := newCursorCodec(testSecret, "keyset").encode("args", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherArgs, err := c.encode("other args", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"no signature", "MTA"},
+		{"bad encoding", "!!.!!"},
+		{"forged", forged},
+		{"other kind", otherKind},
+		{"other args", otherArgs},
+		{"tampered", "MjA" + valid[len("MTA"):]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var offset int
+			if err := c.decode(tt.cursor, "args", &offset); !errors.Is(err, norm.ErrBadCursor) {
+				t.Errorf("decode() error = %v, want %v", err, norm.ErrBadCursor)
+			}
+		})
+	}
+}
+
+func TestNewCursorCodec_Panic_ShortSecret(t *testing.T) {
+	for _, secret := range [][]byte{nil, []byte("secret")} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("newCursorCodec(%q) doesn't panic", secret)
+				}
+			}()
+			newCursorCodec(secret, "offset")
+		}()
+	}
+}
+
+func TestCutPage(t *testing.T) {
+	page := cutPage([]int{1, 2, 3}, 2)
+	if !page.HasNext || len(page.Items) != 2 {
+		t.Errorf("cutPage() = %v, want 2 items with next", page)
+	}
+
+	page = cutPage([]int{1, 2}, 2)
+	if page.HasNext || len(page.Items) != 2 {
+		t.Errorf("cutPage() = %v, want 2 items without next", page)
+	}
+}
@@ -0,0 +1,223 @@
+package sql
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var queryNameRe = regexp.MustCompile(`^--\s*name:\s*(\S+)\s*$`)
+
+// Queries are named query templates loaded from .sql files.
+type Queries struct {
+	m map[string]namedQuery
+}
+
+type namedQuery struct {
+	tpl string
+	pos string
+}
+
+// LoadQueries reads query templates from files matching the pattern (see fs.Glob).
+// Every query starts with the name line, for example:
+//
+//	-- name: GetUser
+//	SELECT "id", "name" FROM "users" WHERE "id" = {{ .A.ID }};
+//
+// Comments following the name line are kept in templates.
+// Works well with embed.FS.
+func LoadQueries(fsys fs.FS, pattern string) (Queries, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return Queries{}, fmt.Errorf("glob %q: %w", pattern, err)
+	}
+
+	if len(files) == 0 {
+		return Queries{}, fmt.Errorf("no files match %q", pattern)
+	}
+
+	q := Queries{m: map[string]namedQuery{}}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return Queries{}, fmt.Errorf("read queries: %w", err)
+		}
+
+		if err := q.parse(file, data); err != nil {
+			return Queries{}, err
+		}
+	}
+
+	return q, nil
+}
+
+func (q Queries) parse(file string, data []byte) error {
+	var (
+		name string
+		pos  string
+		body strings.Builder
+	)
+
+	flush := func() error {
+		if name == "" {
+			return nil
+		}
+
+		tpl := strings.TrimSpace(body.String())
+		if tpl == "" {
+			return fmt.Errorf("%s: query %q is empty", pos, name)
+		}
+
+		if prev, ok := q.m[name]; ok {
+			return fmt.Errorf("%s: duplicate query %q, previous declaration at %s", pos, name, prev.pos)
+		}
+
+		q.m[name] = namedQuery{tpl: tpl, pos: pos}
+		body.Reset()
+
+		return nil
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+
+		if m := queryNameRe.FindStringSubmatch(strings.TrimSpace(text)); m != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+			name, pos = m[1], fmt.Sprintf("%s:%d", file, line)
+			continue
+		}
+
+		if name == "" {
+			trimmed := strings.TrimSpace(text)
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return fmt.Errorf("%s:%d: query without name", file, line)
+			}
+			continue
+		}
+
+		body.WriteString(text)
+		body.WriteByte('\n')
+	}
+
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", file, err)
+	}
+
+	return flush()
+}
+
+// Names returns sorted names of the queries.
+func (q Queries) Names() []string {
+	names := make([]string, 0, len(q.m))
+	for name := range q.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns query template by name.
+func (q Queries) Get(name string) (string, error) {
+	tpls, err := q.lookup(name)
+	if err != nil {
+		return "", err
+	}
+	return tpls[0], nil
+}
+
+// lookup returns templates of the named queries, empty name gives empty template.
+// All missing names are reported.
+func (q Queries) lookup(names ...string) ([]string, error) {
+	tpls := make([]string, len(names))
+
+	var missing []string
+
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+
+		nq, ok := q.m[name]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%q", name))
+			continue
+		}
+
+		tpls[i] = nq.tpl
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("queries not found: %s", strings.Join(missing, ", "))
+	}
+
+	return tpls, nil
+}
+
+// ObjectByName makes validated object of the named queries.
+func ObjectByName[M, A any](db DB, q Queries, c, r, u, d string, opts ...Option) (Object[M, A], error) {
+	t, err := q.lookup(c, r, u, d)
+	if err != nil {
+		return Object[M, A]{}, err
+	}
+
+	o := NewObject[M, A](db, t[0], t[1], t[2], t[3], opts...)
+
+	return o, o.Validate()
+}
+
+// SoftDeleteObjectByName makes validated soft-delete object of the named queries.
+func SoftDeleteObjectByName[M, A any](db DB, q Queries, c, r, u, d, rs, rd string, opts ...Option) (SoftDeleteObject[M, A], error) {
+	t, err := q.lookup(c, r, u, d, rs, rd)
+	if err != nil {
+		return SoftDeleteObject[M, A]{}, err
+	}
+
+	o := NewSoftDeleteObject[M, A](db, t[0], t[1], t[2], t[3], t[4], t[5], opts...)
+
+	return o, o.Validate()
+}
+
+// PersistentObjectByName makes validated persistent object of the named queries.
+func PersistentObjectByName[M, A any](db DB, q Queries, c, r, u string, opts ...Option) (PersistentObject[M, A], error) {
+	t, err := q.lookup(c, r, u)
+	if err != nil {
+		return PersistentObject[M, A]{}, err
+	}
+
+	o := NewPersistentObject[M, A](db, t[0], t[1], t[2], opts...)
+
+	return o, o.Validate()
+}
+
+// ImmutableObjectByName makes validated immutable object of the named queries.
+func ImmutableObjectByName[M, A any](db DB, q Queries, c, r string, opts ...Option) (ImmutableObject[M, A], error) {
+	t, err := q.lookup(c, r)
+	if err != nil {
+		return ImmutableObject[M, A]{}, err
+	}
+
+	o := NewImmutableObject[M, A](db, t[0], t[1], opts...)
+
+	return o, o.Validate()
+}
+
+// ViewByName makes validated view of the named query.
+func ViewByName[M, A any](db DB, q Queries, r string, opts ...Option) (View[M, A], error) {
+	t, err := q.lookup(r)
+	if err != nil {
+		return View[M, A]{}, err
+	}
+
+	v := NewView[M, A](db, t[0], opts...)
+
+	return v, v.Validate()
+}
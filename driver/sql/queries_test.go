@@ -0,0 +1,107 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadQueries(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/users.sql": {Data: []byte(`-- Users queries.
+
+-- name: GetUser
+-- object: User read
+-- field: Name string
+SELECT "id", "name"
+-- the user may be deleted
+FROM "users"
+WHERE "id" = {{ .A.ID }};
+
+-- name: DeleteUser
+DELETE FROM "users" WHERE "id" = {{ .A.ID }};
+`)},
+		"sql/posts.sql": {Data: []byte(`--name:GetPost
+SELECT "id" FROM "posts" WHERE "id" = {{ .A.ID }};
+`)},
+		"sql/readme.txt": {Data: []byte(`not a query`)},
+	}
+
+	q, err := LoadQueries(fsys, "sql/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(q.Names(), ","); got != "DeleteUser,GetPost,GetUser" {
+		t.Errorf("Names() = %s", got)
+	}
+
+	got, err := q.Get("GetUser")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "-- object: User read\n-- field: Name string\nSELECT \"id\", \"name\"\n-- the user may be deleted\nFROM \"users\"\nWHERE \"id\" = {{ .A.ID }};"
+	if got != want {
+		t.Errorf("Get() = %q, want %q", got, want)
+	}
+
+	_, err = ObjectByName[struct{}, struct{ ID string }](nil, q, "", "GetUser", "UpdateUser", "CreateUser")
+	if err == nil || !strings.Contains(err.Error(), `queries not found: "UpdateUser", "CreateUser"`) {
+		t.Errorf("ObjectByName() error = %v", err)
+	}
+
+	_, err = ViewByName[struct{}, struct{ Name string }](nil, q, "GetPost")
+	if err == nil || !strings.Contains(err.Error(), "unknown field .A.ID") {
+		t.Errorf("ViewByName() error = %v", err)
+	}
+
+	_, err = ViewByName[struct{}, struct{ ID string }](nil, q, "GetPost")
+	if err != nil {
+		t.Errorf("ViewByName() error = %v", err)
+	}
+}
+
+func TestLoadQueries_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "no files",
+			files:   fstest.MapFS{},
+			wantErr: "no files match",
+		},
+		{
+			name: "duplicate",
+			files: fstest.MapFS{
+				"a.sql": {Data: []byte("-- name: Q\nSELECT 1;\n")},
+				"b.sql": {Data: []byte("\n-- name: Q\nSELECT 2;\n")},
+			},
+			wantErr: `b.sql:2: duplicate query "Q", previous declaration at a.sql:1`,
+		},
+		{
+			name: "without name",
+			files: fstest.MapFS{
+				"a.sql": {Data: []byte("-- comment\nSELECT 1;\n")},
+			},
+			wantErr: "a.sql:2: query without name",
+		},
+		{
+			name: "empty",
+			files: fstest.MapFS{
+				"a.sql": {Data: []byte("-- name: Q\n\n-- name: P\nSELECT 1;\n")},
+			},
+			wantErr: `a.sql:1: query "Q" is empty`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadQueries(tt.files, "*.sql")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadQueries() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DB is a database of objects: *sql.DB or *Router.
+type DB interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+type primaryKey struct{}
+
+// WithPrimary makes reads of the context go to the primary database of Router,
+// e.g. to read own writes which are not replicated yet.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func primaryValue(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// readDB returns database for reads of the context.
+func readDB(ctx context.Context, db DB) DB {
+	if r, ok := db.(*Router); ok {
+		return r.reader(ctx)
+	}
+	return db
+}
+
+// DefaultHealthCheckInterval is the health check interval of Router balancing reads by latency
+// without WithHealthCheck option.
+const DefaultHealthCheckInterval = 5 * time.Second
+
+// RouterOption configures Router.
+type RouterOption func(*Router)
+
+// WithLatencyBalancing makes Router read from the healthy replica of the least ping latency
+// instead of round-robin. Latency is measured by health checks, it enables them
+// every DefaultHealthCheckInterval unless WithHealthCheck is set.
+func WithLatencyBalancing() RouterOption {
+	return func(r *Router) {
+		r.byLatency = true
+	}
+}
+
+// WithHealthCheck makes Router ping replicas every interval and skip ones which fail to respond in timeout,
+// zero timeout equals the interval.
+func WithHealthCheck(interval, timeout time.Duration) RouterOption {
+	return func(r *Router) {
+		r.interval = interval
+		r.timeout = timeout
+	}
+}
+
+// Router sends writes and reads within transactions to the primary database
+// and the rest reads to replicas. Reads go to the primary when there is no healthy replica.
+type Router struct {
+	primary   *sql.DB
+	replicas  []*replica
+	byLatency bool
+	next      atomic.Uint32
+
+	interval time.Duration
+	timeout  time.Duration
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+type replica struct {
+	db      *sql.DB
+	healthy atomic.Bool
+	latency atomic.Int64
+}
+
+// NewRouter makes router of the primary database and its replicas.
+// Close must be called to stop health checks.
+func NewRouter(primary *sql.DB, replicas []*sql.DB, opts ...RouterOption) *Router {
+	r := &Router{primary: primary}
+
+	for _, db := range replicas {
+		rep := &replica{db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.byLatency && r.interval <= 0 {
+		r.interval = DefaultHealthCheckInterval
+	}
+
+	if r.timeout <= 0 {
+		r.timeout = r.interval
+	}
+
+	if r.interval > 0 && len(r.replicas) > 0 {
+		var ctx context.Context
+		ctx, r.cancel = context.WithCancel(context.Background())
+
+		r.wg.Add(1)
+		go r.run(ctx)
+	}
+
+	return r
+}
+
+// Close stops health checks, databases are left open.
+func (r *Router) Close() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+}
+
+// Primary returns the primary database.
+func (r *Router) Primary() *sql.DB {
+	return r.primary
+}
+
+func (r *Router) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.primary.PrepareContext(ctx, query)
+}
+
+func (r *Router) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return r.primary.QueryContext(ctx, query, args...)
+}
+
+func (r *Router) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return r.primary.BeginTx(ctx, opts)
+}
+
+func (r *Router) reader(ctx context.Context) DB {
+	if primaryValue(ctx) {
+		return r.primary
+	}
+	if rep := r.replica(); rep != nil {
+		return rep.db
+	}
+	return r.primary
+}
+
+func (r *Router) replica() *replica {
+	if r.byLatency {
+		var best *replica
+		for _, rep := range r.replicas {
+			if rep.healthy.Load() && (best == nil || rep.latency.Load() < best.latency.Load()) {
+				best = rep
+			}
+		}
+		return best
+	}
+
+	n := len(r.replicas)
+	if n == 0 {
+		return nil
+	}
+
+	start := int(r.next.Add(1) % uint32(n))
+
+	for i := 0; i < n; i++ {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep
+		}
+	}
+
+	return nil
+}
+
+func (r *Router) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Router) check(ctx context.Context) {
+	for _, rep := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		start := time.Now()
+		err := rep.db.PingContext(pingCtx)
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			rep.healthy.Store(false)
+			continue
+		}
+
+		rep.observe(time.Since(start))
+		rep.healthy.Store(true)
+	}
+}
+
+// observe updates moving average of the replica latency.
+func (rep *replica) observe(d time.Duration) {
+	old := rep.latency.Load()
+	if old == 0 {
+		rep.latency.Store(int64(d))
+		return
+	}
+	rep.latency.Store(old + (int64(d)-old)/4)
+}
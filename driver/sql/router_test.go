@@ -0,0 +1,184 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeConnector struct {
+	err   error
+	delay *atomic.Int64
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return fakeConn{delay: c.delay}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	delay *atomic.Int64
+}
+
+func (c fakeConn) Ping(ctx context.Context) error {
+	if c.delay == nil {
+		return nil
+	}
+	select {
+	case <-time.After(time.Duration(c.delay.Load())):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func newFakeDB(t *testing.T, err error) *sql.DB {
+	t.Helper()
+
+	db := sql.OpenDB(fakeConnector{err: err})
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestRouter_reader(t *testing.T) {
+	primary := newFakeDB(t, nil)
+	r1 := newFakeDB(t, nil)
+	r2 := newFakeDB(t, nil)
+
+	r := NewRouter(primary, []*sql.DB{r1, r2})
+	defer r.Close()
+
+	ctx := context.Background()
+
+	got := map[DB]int{}
+	for i := 0; i < 10; i++ {
+		got[r.reader(ctx)]++
+	}
+
+	if got[r1] != 5 || got[r2] != 5 {
+		t.Errorf("round-robin reads = %d, %d, want 5, 5", got[r1], got[r2])
+	}
+
+	if db := r.reader(WithPrimary(ctx)); db != primary {
+		t.Errorf("reader(WithPrimary()) is not the primary")
+	}
+
+	if db := readDB(ctx, primary); db != primary {
+		t.Errorf("readDB(*sql.DB) is not the same database")
+	}
+
+	r.replicas[0].healthy.Store(false)
+	r.replicas[1].healthy.Store(false)
+
+	if db := r.reader(ctx); db != primary {
+		t.Errorf("reader() without healthy replicas is not the primary")
+	}
+}
+
+func TestRouter_reader_WithLatencyBalancing(t *testing.T) {
+	r1 := newFakeDB(t, nil)
+	r2 := newFakeDB(t, nil)
+
+	r := NewRouter(newFakeDB(t, nil), []*sql.DB{r1, r2}, WithLatencyBalancing())
+	defer r.Close()
+
+	r.replicas[0].observe(20 * time.Millisecond)
+	r.replicas[1].observe(10 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		if db := r.reader(context.Background()); db != r2 {
+			t.Fatalf("reader() is not the fastest replica")
+		}
+	}
+}
+
+func TestRouter_WithLatencyBalancing_HealthCheck(t *testing.T) {
+	var d1, d2 atomic.Int64
+	d1.Store(int64(20 * time.Millisecond))
+
+	r1 := sql.OpenDB(fakeConnector{delay: &d1})
+	r2 := sql.OpenDB(fakeConnector{delay: &d2})
+	defer r1.Close()
+	defer r2.Close()
+
+	lazy := NewRouter(newFakeDB(t, nil), []*sql.DB{r1, r2}, WithLatencyBalancing())
+	waitReader(t, lazy, r2)
+	lazy.Close()
+
+	if lazy.interval != DefaultHealthCheckInterval {
+		t.Errorf("health check interval = %v, want %v", lazy.interval, DefaultHealthCheckInterval)
+	}
+
+	r := NewRouter(newFakeDB(t, nil), []*sql.DB{r1, r2}, WithLatencyBalancing(), WithHealthCheck(time.Millisecond, time.Second))
+	defer r.Close()
+
+	waitReader(t, r, r2)
+
+	d1.Store(0)
+	d2.Store(int64(20 * time.Millisecond))
+
+	waitReader(t, r, r1)
+}
+
+// waitReader waits until health checks make want the reader of the router.
+func waitReader(t *testing.T, r *Router, want *sql.DB) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for r.reader(context.Background()) != want {
+		if time.Now().After(deadline) {
+			t.Fatal("reader() is not the fastest replica")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRouter_check(t *testing.T) {
+	healthy := newFakeDB(t, nil)
+	broken := newFakeDB(t, errors.New("connection refused"))
+
+	r := NewRouter(newFakeDB(t, nil), []*sql.DB{broken, healthy}, WithHealthCheck(time.Millisecond, 0))
+
+	deadline := time.Now().Add(time.Second)
+	for r.replicas[0].healthy.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("broken replica is not ejected")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	r.Close()
+
+	for i := 0; i < 4; i++ {
+		if db := r.reader(context.Background()); db != healthy {
+			t.Fatalf("reader() is not the healthy replica")
+		}
+	}
+
+	if !r.replicas[1].healthy.Load() || r.replicas[1].latency.Load() == 0 {
+		t.Errorf("healthy replica state = %v, latency %d", r.replicas[1].healthy.Load(), r.replicas[1].latency.Load())
+	}
+}
@@ -0,0 +1,152 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/WinPooh32/norm"
+)
+
+// Counter scans count from the single column of the first row.
+// The count is zero when query returns no rows.
+type Counter[A any] struct {
+	db      DB
+	tpl     string
+	scope   scopeOptions
+	timeout opTimeout
+}
+
+func NewCounter[A any](db DB, r string, opts ...Option) Counter[A] {
+	o := newOptions(opts)
+	return Counter[A]{db: db, tpl: r, scope: o.scope, timeout: o.timeout(norm.OpRead)}
+}
+
+func (c Counter[A]) Count(ctx context.Context, args A) (n int64, err error) {
+	err = c.timeout.run(ctx, func(ctx context.Context) error {
+		n, err = c.count(ctx, args)
+		return err
+	})
+	return n, err
+}
+
+func (c Counter[A]) count(ctx context.Context, args A) (n int64, err error) {
+	pr := newPreparer(txValue(ctx), readDB(ctx, c.db))
+
+	scope, err := c.scope.bind(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := query(ctx, pr, c.tpl, a[A]{A: args, C: scope})
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("read rows: %w", err)
+		}
+		return 0, nil
+	}
+
+	if err := rows.Scan(&n); err != nil {
+		return 0, fmt.Errorf("scan count: %w", err)
+	}
+
+	return n, nil
+}
+
+func (c Counter[A]) Validate() error {
+	return validate(checkOf[a[A]]("count", c.tpl))
+}
+
+func (c Counter[A]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, c.db, checkOf[a[A]]("count", c.tpl))
+}
+
+// Exister checks whether query returns any row, rest rows are not read.
+// Queries returning the flag column, e.g. SELECT EXISTS(...), need WithExistsFlag.
+type Exister[A any] struct {
+	db      DB
+	tpl     string
+	flag    bool
+	scope   scopeOptions
+	timeout opTimeout
+}
+
+func NewExister[A any](db DB, r string, opts ...Option) Exister[A] {
+	o := newOptions(opts)
+	return Exister[A]{db: db, tpl: r, flag: o.flag, scope: o.scope, timeout: o.timeout(norm.OpRead)}
+}
+
+func (e Exister[A]) Exists(ctx context.Context, args A) (ok bool, err error) {
+	err = e.timeout.run(ctx, func(ctx context.Context) error {
+		ok, err = e.exists(ctx, args)
+		return err
+	})
+	return ok, err
+}
+
+func (e Exister[A]) exists(ctx context.Context, args A) (ok bool, err error) {
+	pr := newPreparer(txValue(ctx), readDB(ctx, e.db))
+
+	scope, err := e.scope.bind(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := query(ctx, pr, e.tpl, a[A]{A: args, C: scope})
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return false, fmt.Errorf("read rows: %w", err)
+		}
+		return false, nil
+	}
+
+	if !e.flag {
+		return true, nil
+	}
+
+	var v any
+
+	if err := rows.Scan(&v); err != nil {
+		return false, fmt.Errorf("scan flag: %w", err)
+	}
+
+	return existsFlag(v)
+}
+
+// existsFlag converts flag column value, drivers without boolean type return it as integer 0 or 1.
+func existsFlag(v any) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case int64:
+		return v != 0, nil
+	case []byte:
+		return existsFlag(string(v))
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("parse flag %q: %w", v, err)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("unsupported flag type %T", v)
+	}
+}
+
+func (e Exister[A]) Validate() error {
+	return validate(checkOf[a[A]]("exists", e.tpl))
+}
+
+func (e Exister[A]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, e.db, checkOf[a[A]]("exists", e.tpl))
+}
@@ -0,0 +1,64 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	qq := []string{
+		`CREATE TABLE "tests" ("id" text PRIMARY KEY, "active" boolean NOT NULL);`,
+		`INSERT INTO "tests" VALUES ('id01', false);`,
+	}
+	for _, q := range qq {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return db
+}
+
+func TestExister_Exists(t *testing.T) {
+	db := newSQLite(t)
+
+	const (
+		selectFlag   = `SELECT "active" FROM "tests" WHERE "id" = {{ .A }};`
+		selectExists = `SELECT EXISTS (SELECT 1 FROM "tests" WHERE "id" = {{ .A }});`
+	)
+
+	tests := []struct {
+		name string
+		e    Exister[string]
+		id   string
+		want bool
+	}{
+		{"false column exists", NewExister[string](db, selectFlag), "id01", true},
+		{"no rows", NewExister[string](db, selectFlag), "-1", false},
+		{"integer flag true", NewExister[string](db, selectExists, WithExistsFlag()), "id01", true},
+		{"integer flag false", NewExister[string](db, selectExists, WithExistsFlag()), "-1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.e.Exists(context.Background(), tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Exists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/WinPooh32/norm"
+)
+
+type scanMode int
+
+const (
+	// scanRow scans the first row into struct.
+	scanRow scanMode = iota
+	// scanRows scans all rows into slice of structs.
+	scanRows
+	// scanScalar scans the single column of the first row.
+	scanScalar
+	// scanScalars scans the single column of all rows into slice.
+	scanScalars
+	// scanMap scans the first row into map[string]any.
+	scanMap
+	// scanMaps scans all rows into []map[string]any.
+	scanMaps
+	// scanDynRow scans the first row into Row.
+	scanDynRow
+	// scanDynRows scans all rows into []Row.
+	scanDynRows
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+	mapType     = reflect.TypeOf(map[string]any{})
+	rowType     = reflect.TypeOf(Row{})
+)
+
+func scanModeOf[M any]() scanMode {
+	return scanModeFor(reflect.TypeOf((*M)(nil)).Elem())
+}
+
+func scanModeFor(t reflect.Type) scanMode {
+	switch {
+	case t == mapType:
+		return scanMap
+	case t == reflect.SliceOf(mapType):
+		return scanMaps
+	case t == rowType:
+		return scanDynRow
+	case t == reflect.SliceOf(rowType):
+		return scanDynRows
+	case IsScalar(t):
+		return scanScalar
+	case t.Kind() == reflect.Slice && IsScalar(t.Elem()):
+		return scanScalars
+	case t.Kind() == reflect.Slice:
+		return scanRows
+	default:
+		return scanRow
+	}
+}
+
+// IsScalar reports whether values of the type are scanned from a single column:
+// basic types, []byte, time.Time, sql.Scanner implementers and pointers to them.
+// Other types are scanned from the whole row by Mapper.
+func IsScalar(t reflect.Type) bool {
+	if t == timeType || reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Pointer:
+		return IsScalar(t.Elem())
+	default:
+		return false
+	}
+}
+
+// resultRows is the current result set of *sql.Rows.
+type resultRows interface {
+	rowsScanner
+	ColumnTypes() ([]*sql.ColumnType, error)
+}
+
+func scanInto[M any](mode scanMode, o scanOptions, value *M, rows resultRows) error {
+	return scanValue(mode, o, reflect.ValueOf(value), rows)
+}
+
+// scanValue scans rows into the value pointed by ptr.
+func scanValue(mode scanMode, o scanOptions, ptr reflect.Value, rows resultRows) (err error) {
+	value := ptr.Interface()
+
+	switch mode {
+	case scanScalar:
+		err = scanOneColumn(value, rows)
+		if errors.Is(err, sql.ErrNoRows) {
+			return norm.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("scan one row: %w", err)
+		}
+
+	case scanScalars:
+		err = scanOneColumnRows(value, rows)
+		if err != nil {
+			return fmt.Errorf("scan rows: %w", err)
+		}
+
+	case scanMap, scanMaps, scanDynRow, scanDynRows:
+		err = scanDynamic(mode, value, rows)
+		if errors.Is(err, sql.ErrNoRows) {
+			return norm.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("scan rows: %w", err)
+		}
+
+	case scanRows:
+		err = o.scanStructs(ptr.Elem(), rows, false)
+		if err != nil {
+			return fmt.Errorf("scan rows: %w", err)
+		}
+
+	default:
+		one := reflect.New(reflect.SliceOf(ptr.Type().Elem())).Elem()
+
+		err = o.scanStructs(one, rows, true)
+		if err != nil {
+			return fmt.Errorf("scan one row: %w", err)
+		}
+		if one.Len() == 0 {
+			return norm.ErrNotFound
+		}
+
+		ptr.Elem().Set(one.Index(0))
+	}
+
+	return nil
+}
+
+func scanOneColumn(dst any, rows resultRows) error {
+	defer rows.Close()
+
+	if err := checkOneColumn(rows); err != nil {
+		return err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := rows.Scan(dst); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+func scanOneColumnRows(dst any, rows resultRows) error {
+	defer rows.Close()
+
+	if err := checkOneColumn(rows); err != nil {
+		return err
+	}
+
+	slice := reflect.ValueOf(dst).Elem()
+	elemType := slice.Type().Elem()
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+
+		if err := rows.Scan(elem.Interface()); err != nil {
+			return err
+		}
+
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+func checkOneColumn(rows resultRows) error {
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	if len(cols) != 1 {
+		return fmt.Errorf("scalar value expects exactly one column, query returned %d: %v", len(cols), cols)
+	}
+
+	return nil
+}
+
+// Row is a dynamically scanned row.
+// Values are kept as returned by the database driver.
+type Row struct {
+	Columns []*sql.ColumnType
+	Values  []any
+}
+
+// Get returns value of the named column.
+func (r Row) Get(name string) (value any, ok bool) {
+	for i, c := range r.Columns {
+		if c.Name() == name {
+			return r.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+// Map returns row values by column names.
+func (r Row) Map() map[string]any {
+	m := make(map[string]any, len(r.Columns))
+	for i, c := range r.Columns {
+		m[c.Name()] = r.Values[i]
+	}
+	return m
+}
+
+func scanDynamic(mode scanMode, dst any, rows resultRows) error {
+	defer rows.Close()
+
+	cols, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	one := mode == scanMap || mode == scanDynRow
+
+	var out []Row
+
+	for rows.Next() {
+		row := Row{
+			Columns: cols,
+			Values:  make([]any, len(cols)),
+		}
+
+		ptrs := make([]any, len(cols))
+		for i := range row.Values {
+			ptrs[i] = &row.Values[i]
+		}
+
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+
+		out = append(out, row)
+
+		if one {
+			break
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if one && len(out) == 0 {
+		return sql.ErrNoRows
+	}
+
+	switch v := dst.(type) {
+	case *map[string]any:
+		*v = out[0].Map()
+	case *Row:
+		*v = out[0]
+	case *[]map[string]any:
+		*v = make([]map[string]any, 0, len(out))
+		for _, row := range out {
+			*v = append(*v, row.Map())
+		}
+	case *[]Row:
+		*v = out
+	}
+
+	return rows.Close()
+}
@@ -0,0 +1,43 @@
+package sql
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestScanModeOf(t *testing.T) {
+	type model struct {
+		ID string `db:"id"`
+	}
+
+	tests := []struct {
+		name string
+		got  scanMode
+		want scanMode
+	}{
+		{"struct", scanModeOf[model](), scanRow},
+		{"struct slice", scanModeOf[[]model](), scanRows},
+		{"string", scanModeOf[string](), scanScalar},
+		{"int64", scanModeOf[int64](), scanScalar},
+		{"bytes", scanModeOf[[]byte](), scanScalar},
+		{"time", scanModeOf[time.Time](), scanScalar},
+		{"pointer", scanModeOf[*string](), scanScalar},
+		{"scanner", scanModeOf[sql.NullString](), scanScalar},
+		{"string slice", scanModeOf[[]string](), scanScalars},
+		{"bytes slice", scanModeOf[[][]byte](), scanScalars},
+		{"time slice", scanModeOf[[]time.Time](), scanScalars},
+		{"scanner slice", scanModeOf[[]sql.NullInt64](), scanScalars},
+		{"map", scanModeOf[map[string]any](), scanMap},
+		{"map slice", scanModeOf[[]map[string]any](), scanMaps},
+		{"row", scanModeOf[Row](), scanDynRow},
+		{"row slice", scanModeOf[[]Row](), scanDynRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("scanModeOf() = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
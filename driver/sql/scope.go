@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/WinPooh32/norm"
+)
+
+// ErrMissingScope is norm.ErrMissingScope, so it doesn't trip circuit breakers.
+var ErrMissingScope = norm.ErrMissingScope
+
+// Scope holds values of the context exposed to templates as .C, e.g. {{ .C.tenant }}.
+type Scope map[string]any
+
+type scopeKey struct{}
+
+// WithScope returns context with the scope value of key, e.g. tenant or user of the request.
+// Values of the parent context are kept.
+func WithScope(ctx context.Context, key string, value any) context.Context {
+	parent := scopeValue(ctx)
+
+	s := make(Scope, len(parent)+1)
+	for k, v := range parent {
+		s[k] = v
+	}
+	s[key] = value
+
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// ScopeFromContext returns scope values set by WithScope.
+func ScopeFromContext(ctx context.Context) Scope {
+	return scopeValue(ctx)
+}
+
+func scopeValue(ctx context.Context) Scope {
+	s, _ := ctx.Value(scopeKey{}).(Scope)
+	return s
+}
+
+type scopeOptions struct {
+	required []string
+	settings []localSetting
+}
+
+// localSetting is a run-time parameter set to the scope value within transactions.
+type localSetting struct {
+	name string
+	key  string
+}
+
+// bind returns scope of the context for templates. It fails when a required key is missing
+// and applies local settings when the context holds a transaction, a missing key of a setting fails it too.
+func (o scopeOptions) bind(ctx context.Context) (Scope, error) {
+	s := scopeValue(ctx)
+
+	for _, key := range o.required {
+		if _, ok := s[key]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingScope, key)
+		}
+	}
+
+	if len(o.settings) == 0 {
+		return s, nil
+	}
+
+	tx := txValue(ctx)
+	if tx == nil {
+		return s, nil
+	}
+
+	for _, set := range o.settings {
+		if _, ok := s[set.key]; !ok {
+			return nil, fmt.Errorf("%w: %q of setting %s", ErrMissingScope, set.key, set.name)
+		}
+	}
+
+	stmt, err := tq.placeholder.Format("SELECT set_config(?, ?, true)")
+	if err != nil {
+		return nil, fmt.Errorf("format set_config query: %w", err)
+	}
+
+	for _, set := range o.settings {
+		if _, err := tx.ExecContext(ctx, stmt, set.name, fmt.Sprint(s[set.key])); err != nil {
+			return nil, fmt.Errorf("set %s: %w", set.name, err)
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithScope(t *testing.T) {
+	ctx := WithScope(context.Background(), "tenant", "t1")
+	child := WithScope(ctx, "user", 7)
+
+	if got, want := ScopeFromContext(ctx), (Scope{"tenant": "t1"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ScopeFromContext(parent) = %v, want %v", got, want)
+	}
+
+	if got, want := ScopeFromContext(child), (Scope{"tenant": "t1", "user": 7}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ScopeFromContext(child) = %v, want %v", got, want)
+	}
+}
+
+func TestScopeOptions_Bind(t *testing.T) {
+	o := newOptions([]Option{WithRequiredScope("tenant")}).scope
+
+	if _, err := o.bind(context.Background()); !errors.Is(err, ErrMissingScope) {
+		t.Errorf("bind() error = %v, want %v", err, ErrMissingScope)
+	}
+
+	ctx := WithScope(context.Background(), "tenant", "t1")
+
+	s, err := o.bind(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s["tenant"] != "t1" {
+		t.Errorf("bind() = %v, want tenant t1", s)
+	}
+}
+
+func TestScopeOptions_Bind_LocalSettingMissing(t *testing.T) {
+	db := newSQLite(t)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	o := newOptions([]Option{WithLocalSetting("app.tenant", "tenant")}).scope
+
+	if _, err := o.bind(context.Background()); err != nil {
+		t.Errorf("bind() outside transaction error = %v", err)
+	}
+
+	if _, err := o.bind(WithTransaction(context.Background(), tx)); !errors.Is(err, ErrMissingScope) {
+		t.Errorf("bind() error = %v, want %v", err, ErrMissingScope)
+	}
+}
+
+func TestObject_RequiredScope(t *testing.T) {
+	db := newFakeDB(t, nil)
+	obj := NewObject[int, int](db, `c`, `SELECT {{ .C.tenant }}`, `u`, `d`, WithRequiredScope("tenant"))
+
+	if _, err := obj.Read(context.Background(), 1); !errors.Is(err, ErrMissingScope) {
+		t.Errorf("Read() error = %v, want %v", err, ErrMissingScope)
+	}
+
+	if err := obj.Create(context.Background(), 1, 1); !errors.Is(err, ErrMissingScope) {
+		t.Errorf("Create() error = %v, want %v", err, ErrMissingScope)
+	}
+
+	if err := obj.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestCompile_Scope(t *testing.T) {
+	stmt, args, err := tq.Compile(`SELECT * FROM "t" WHERE "tenant_id" = {{ .C.tenant }} AND "id" = {{ .A }}`,
+		a[int]{A: 1, C: Scope{"tenant": "t1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `SELECT * FROM "t" WHERE "tenant_id" = $1 AND "id" = $2`; stmt != want {
+		t.Errorf("stmt = %q, want %q", stmt, want)
+	}
+	if want := []any{"t1", 1}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+}
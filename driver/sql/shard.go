@@ -0,0 +1,204 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// ErrShardedTx is returned by ShardedView for contexts with transaction,
+// since a transaction can't be shared by concurrent reads of different shards.
+var ErrShardedTx = errors.New("sharded view can't read within transaction")
+
+// ErrNoShards is returned by NewSharded and NewShardedView without databases.
+var ErrNoShards = errors.New("no shards")
+
+// HashShard returns shard function which spreads args over n shards by FNV-1a hash of the key.
+// It panics when n is not positive.
+func HashShard[A any](n int, key func(args A) string) func(args A) int {
+	if n <= 0 {
+		panic(fmt.Sprintf("sql: HashShard of %d shards", n))
+	}
+	return func(args A) int {
+		h := fnv.New32a()
+		h.Write([]byte(key(args)))
+		return int(h.Sum32() % uint32(n))
+	}
+}
+
+// Sharded routes object operations to the shard database chosen by args.
+// Transaction of the context must be started on the same shard.
+type Sharded[M, A any] struct {
+	shards []Object[M, A]
+	shard  func(args A) int
+}
+
+// NewSharded makes object of the shard databases, shard returns index of the args shard.
+// It fails with ErrNoShards when dbs are empty.
+func NewSharded[M, A any](dbs []DB, shard func(args A) int, c, r, u, d string, opts ...Option) (Sharded[M, A], error) {
+	s := Sharded[M, A]{shard: shard}
+	if len(dbs) == 0 {
+		return s, ErrNoShards
+	}
+	for _, db := range dbs {
+		s.shards = append(s.shards, NewObject[M, A](db, c, r, u, d, opts...))
+	}
+	return s, nil
+}
+
+func (s Sharded[M, A]) Create(ctx context.Context, args A, value M) error {
+	o, err := s.object(args)
+	if err != nil {
+		return err
+	}
+	return o.Create(ctx, args, value)
+}
+
+func (s Sharded[M, A]) Read(ctx context.Context, args A) (value M, err error) {
+	o, err := s.object(args)
+	if err != nil {
+		return value, err
+	}
+	return o.Read(ctx, args)
+}
+
+func (s Sharded[M, A]) Update(ctx context.Context, args A, value M) error {
+	o, err := s.object(args)
+	if err != nil {
+		return err
+	}
+	return o.Update(ctx, args, value)
+}
+
+func (s Sharded[M, A]) Delete(ctx context.Context, args A) error {
+	o, err := s.object(args)
+	if err != nil {
+		return err
+	}
+	return o.Delete(ctx, args)
+}
+
+func (s Sharded[M, A]) Validate() error {
+	if len(s.shards) == 0 {
+		return ErrNoShards
+	}
+	return s.shards[0].Validate()
+}
+
+// ValidateDB validates templates on every shard.
+func (s Sharded[M, A]) ValidateDB(ctx context.Context) error {
+	for i, o := range s.shards {
+		if err := o.ValidateDB(ctx); err != nil {
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s Sharded[M, A]) object(args A) (o Object[M, A], err error) {
+	i := s.shard(args)
+	if i < 0 || i >= len(s.shards) {
+		return o, fmt.Errorf("shard %d is out of range [0, %d)", i, len(s.shards))
+	}
+	return s.shards[i], nil
+}
+
+// ShardedView reads values of all shards concurrently and merges them.
+type ShardedView[T, A any] struct {
+	shards []View[[]T, A]
+	less   func(a, b T) bool
+}
+
+// NewShardedView makes view of the shard databases. It fails with ErrNoShards when dbs are empty.
+func NewShardedView[T, A any](dbs []DB, r string, opts ...Option) (ShardedView[T, A], error) {
+	var v ShardedView[T, A]
+	if len(dbs) == 0 {
+		return v, ErrNoShards
+	}
+	for _, db := range dbs {
+		v.shards = append(v.shards, NewView[[]T, A](db, r, opts...))
+	}
+	return v, nil
+}
+
+// Ordered returns view which sorts merged values by less, values of equal order keep order of shards.
+func (v ShardedView[T, A]) Ordered(less func(a, b T) bool) ShardedView[T, A] {
+	v.less = less
+	return v
+}
+
+// Read reads all shards, the first failed read cancels the rest ones.
+// It fails with ErrShardedTx when the context holds transaction set by WithTransaction.
+func (v ShardedView[T, A]) Read(ctx context.Context, args A) (values []T, err error) {
+	if txValue(ctx) != nil {
+		return nil, ErrShardedTx
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]T, len(v.shards))
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+
+	for i, shard := range v.shards {
+		i, shard := i, shard
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			r, err := shard.Read(ctx, args)
+			if err != nil {
+				once.Do(func() {
+					first = fmt.Errorf("shard %d: %w", i, err)
+					cancel()
+				})
+				return
+			}
+
+			results[i] = r
+		}()
+	}
+
+	wg.Wait()
+
+	if first != nil {
+		return nil, first
+	}
+
+	for _, r := range results {
+		values = append(values, r...)
+	}
+
+	if v.less != nil {
+		sort.SliceStable(values, func(i, j int) bool {
+			return v.less(values[i], values[j])
+		})
+	}
+
+	return values, nil
+}
+
+func (v ShardedView[T, A]) Validate() error {
+	if len(v.shards) == 0 {
+		return ErrNoShards
+	}
+	return v.shards[0].Validate()
+}
+
+func (v ShardedView[T, A]) ValidateDB(ctx context.Context) error {
+	for i, s := range v.shards {
+		if err := s.ValidateDB(ctx); err != nil {
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestHashShard(t *testing.T) {
+	shard := HashShard(4, func(id string) string { return id })
+
+	seen := map[int]bool{}
+	for i := 0; i < 100; i++ {
+		id := strconv.Itoa(i)
+
+		n := shard(id)
+		if n < 0 || n >= 4 {
+			t.Fatalf("shard(%q) = %d, want in [0, 4)", id, n)
+		}
+		if shard(id) != n {
+			t.Fatalf("shard(%q) is not stable", id)
+		}
+
+		seen[n] = true
+	}
+
+	if len(seen) != 4 {
+		t.Errorf("shards used = %d, want 4", len(seen))
+	}
+}
+
+func TestSharded_Error_OutOfRange(t *testing.T) {
+	dbs := []DB{newFakeDB(t, nil), newFakeDB(t, nil)}
+	s, err := NewSharded[struct{}, int](dbs, func(args int) int { return args }, "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Read(context.Background(), 2)
+	if err == nil || err.Error() != "shard 2 is out of range [0, 2)" {
+		t.Errorf("Read() error = %v", err)
+	}
+}
+
+func TestHashShard_Panic_NoShards(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	HashShard(0, func(id string) string { return id })
+}
+
+func TestSharded_Error_NoShards(t *testing.T) {
+	if _, err := NewSharded[struct{}, int](nil, func(args int) int { return args }, "", "", "", ""); !errors.Is(err, ErrNoShards) {
+		t.Errorf("NewSharded() error = %v, want %v", err, ErrNoShards)
+	}
+
+	if _, err := NewShardedView[string, struct{}](nil, `SELECT "id" FROM "tests";`); !errors.Is(err, ErrNoShards) {
+		t.Errorf("NewShardedView() error = %v, want %v", err, ErrNoShards)
+	}
+}
+
+func TestShardedView_Error_Transaction(t *testing.T) {
+	db := newSQLite(t)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	v, err := NewShardedView[string, struct{}]([]DB{db, db}, `SELECT "id" FROM "tests";`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = v.Read(WithTransaction(context.Background(), tx), struct{}{})
+	if !errors.Is(err, ErrShardedTx) {
+		t.Errorf("Read() error = %v, want %v", err, ErrShardedTx)
+	}
+}
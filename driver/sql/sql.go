@@ -3,26 +3,37 @@ package sql
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
-	"reflect"
 
 	"github.com/VauntDev/tqla"
-	"github.com/blockloop/scan/v2"
 
 	"github.com/WinPooh32/norm"
 )
 
-var tq, _ = tqla.New(tqla.WithPlaceHolder(tqla.Dollar))
+var tq = compiler{placeholder: tqla.Dollar, quote: DoubleQuotes}
 
 func SetPlaceHolder(p tqla.Placeholder) {
-	tq, _ = tqla.New(tqla.WithPlaceHolder(p))
+	tq.placeholder = p
+}
+
+// SetQuoter sets quoting of identifiers made by templates and Bulk, DoubleQuotes by default.
+// Use Backticks for MySQL.
+func SetQuoter(q Quoter) {
+	tq.quote = q
 }
 
 type txKey struct{}
 
+// WithTransaction makes objects run queries of the context in tx instead of their DB.
+// Reads of norm.Batch run one by one then, as tx is unsafe for concurrent use.
 func WithTransaction(ctx context.Context, tx *sql.Tx) context.Context {
-	return context.WithValue(ctx, txKey{}, tx)
+	return context.WithValue(norm.WithSerialReads(ctx), txKey{}, tx)
+}
+
+// TxFromContext returns transaction set by WithTransaction.
+func TxFromContext(ctx context.Context) (tx *sql.Tx, ok bool) {
+	tx = txValue(ctx)
+	return tx, tx != nil
 }
 
 func txValue(ctx context.Context) *sql.Tx {
@@ -40,6 +51,15 @@ type Object[M, A any] struct {
 	deleter[M, A]
 }
 
+type SoftDeleteObject[M, A any] struct {
+	creator[M, A]
+	reader[M, A]
+	updater[M, A]
+	deleter[M, A]
+	restorer[M, A]
+	deletedReader[M, A]
+}
+
 type PersistentObject[M, A any] struct {
 	creator[M, A]
 	reader[M, A]
@@ -55,51 +75,152 @@ type View[M, A any] struct {
 	reader[M, A]
 }
 
-func NewObject[M, A any](db *sql.DB, c, r, u, d string) Object[M, A] {
+func NewObject[M, A any](db DB, c, r, u, d string, opts ...Option) Object[M, A] {
+	o := newOptions(opts)
 	return Object[M, A]{
-		creator: creator[M, A]{writer[M, A]{db, c}},
-		reader:  reader[M, A]{db, r, isSlice[M]()},
-		updater: updater[M, A]{writer[M, A]{db, u}},
-		deleter: deleter[M, A]{writer[M, A]{db, d}},
+		creator: creator[M, A]{writer[M, A]{db, c, o.rows(norm.OpCreate), o.scope, o.timeout(norm.OpCreate)}},
+		reader:  reader[M, A]{db, r, scanModeOf[M](), o.scan, o.scope, o.timeout(norm.OpRead)},
+		updater: updater[M, A]{writer[M, A]{db, u, o.rows(norm.OpUpdate), o.scope, o.timeout(norm.OpUpdate)}},
+		deleter: deleter[M, A]{writer[M, A]{db, d, o.rows(norm.OpDelete), o.scope, o.timeout(norm.OpDelete)}},
 	}
 }
 
-func NewPersistentObject[M, A any](db *sql.DB, c, r, u string) PersistentObject[M, A] {
+// NewSoftDeleteObject makes object which never removes rows physically.
+// The d and rs templates must mark and unmark rows as deleted (e.g. set "deleted_at"),
+// r must exclude marked rows and rd must read rows regardless of the mark.
+func NewSoftDeleteObject[M, A any](db DB, c, r, u, d, rs, rd string, opts ...Option) SoftDeleteObject[M, A] {
+	o := newOptions(opts)
+	return SoftDeleteObject[M, A]{
+		creator:       creator[M, A]{writer[M, A]{db, c, o.rows(norm.OpCreate), o.scope, o.timeout(norm.OpCreate)}},
+		reader:        reader[M, A]{db, r, scanModeOf[M](), o.scan, o.scope, o.timeout(norm.OpRead)},
+		updater:       updater[M, A]{writer[M, A]{db, u, o.rows(norm.OpUpdate), o.scope, o.timeout(norm.OpUpdate)}},
+		deleter:       deleter[M, A]{writer[M, A]{db, d, o.rows(norm.OpDelete), o.scope, o.timeout(norm.OpDelete)}},
+		restorer:      restorer[M, A]{writer[M, A]{db, rs, o.rows(norm.OpRestore), o.scope, o.timeout(norm.OpRestore)}},
+		deletedReader: deletedReader[M, A]{reader[M, A]{db, rd, scanModeOf[M](), o.scan, o.scope, o.timeout(norm.OpRead)}},
+	}
+}
+
+func NewPersistentObject[M, A any](db DB, c, r, u string, opts ...Option) PersistentObject[M, A] {
+	o := newOptions(opts)
 	return PersistentObject[M, A]{
-		creator: creator[M, A]{writer[M, A]{db, c}},
-		reader:  reader[M, A]{db, r, isSlice[M]()},
-		updater: updater[M, A]{writer[M, A]{db, u}},
+		creator: creator[M, A]{writer[M, A]{db, c, o.rows(norm.OpCreate), o.scope, o.timeout(norm.OpCreate)}},
+		reader:  reader[M, A]{db, r, scanModeOf[M](), o.scan, o.scope, o.timeout(norm.OpRead)},
+		updater: updater[M, A]{writer[M, A]{db, u, o.rows(norm.OpUpdate), o.scope, o.timeout(norm.OpUpdate)}},
 	}
 }
 
-func NewImmutableObject[M, A any](db *sql.DB, c, r string) ImmutableObject[M, A] {
+func NewImmutableObject[M, A any](db DB, c, r string, opts ...Option) ImmutableObject[M, A] {
+	o := newOptions(opts)
 	return ImmutableObject[M, A]{
-		creator: creator[M, A]{writer[M, A]{db, c}},
-		reader:  reader[M, A]{db, r, isSlice[M]()},
+		creator: creator[M, A]{writer[M, A]{db, c, o.rows(norm.OpCreate), o.scope, o.timeout(norm.OpCreate)}},
+		reader:  reader[M, A]{db, r, scanModeOf[M](), o.scan, o.scope, o.timeout(norm.OpRead)},
 	}
 }
 
-func NewView[M, A any](db *sql.DB, r string) View[M, A] {
+func NewView[M, A any](db DB, r string, opts ...Option) View[M, A] {
+	o := newOptions(opts)
 	return View[M, A]{
 		reader: reader[M, A]{
-			db:        db,
-			tpl:       r,
-			scanSlice: isSlice[M](),
+			db:      db,
+			tpl:     r,
+			mode:    scanModeOf[M](),
+			scan:    o.scan,
+			scope:   o.scope,
+			timeout: o.timeout(norm.OpRead),
 		},
 	}
 }
 
+// Validate checks that templates are parsed and refer to existing fields of M and A.
+func (o Object[M, A]) Validate() error {
+	return validate(o.checks()...)
+}
+
+// ValidateDB validates templates and prepares statements on the database to check queries syntax.
+func (o Object[M, A]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, o.reader.db, o.checks()...)
+}
+
+func (o Object[M, A]) checks() []tplCheck {
+	return []tplCheck{
+		o.creator.check("create"),
+		o.reader.check("read"),
+		o.updater.check("update"),
+		o.deleter.check("delete"),
+	}
+}
+
+func (o SoftDeleteObject[M, A]) Validate() error {
+	return validate(o.checks()...)
+}
+
+func (o SoftDeleteObject[M, A]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, o.reader.db, o.checks()...)
+}
+
+func (o SoftDeleteObject[M, A]) checks() []tplCheck {
+	return []tplCheck{
+		o.creator.check("create"),
+		o.reader.check("read"),
+		o.updater.check("update"),
+		o.deleter.check("delete"),
+		o.restorer.check("restore"),
+		o.deletedReader.r.check("read with deleted"),
+	}
+}
+
+func (o PersistentObject[M, A]) Validate() error {
+	return validate(o.checks()...)
+}
+
+func (o PersistentObject[M, A]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, o.reader.db, o.checks()...)
+}
+
+func (o PersistentObject[M, A]) checks() []tplCheck {
+	return []tplCheck{
+		o.creator.check("create"),
+		o.reader.check("read"),
+		o.updater.check("update"),
+	}
+}
+
+func (o ImmutableObject[M, A]) Validate() error {
+	return validate(o.checks()...)
+}
+
+func (o ImmutableObject[M, A]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, o.reader.db, o.checks()...)
+}
+
+func (o ImmutableObject[M, A]) checks() []tplCheck {
+	return []tplCheck{
+		o.creator.check("create"),
+		o.reader.check("read"),
+	}
+}
+
+func (v View[M, A]) Validate() error {
+	return validate(v.reader.check("read"))
+}
+
+func (v View[M, A]) ValidateDB(ctx context.Context) error {
+	return validateDB(ctx, v.reader.db, v.reader.check("read"))
+}
+
 type preparer interface {
 	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
 }
 
 type a[A any] struct {
 	A A
+	C Scope
 }
 
 type ma[M, A any] struct {
 	M M
 	A A
+	C Scope
 }
 
 type creator[M, A any] struct {
@@ -107,6 +228,9 @@ type creator[M, A any] struct {
 }
 
 func (c creator[M, A]) Create(ctx context.Context, args A, value M) error {
+	if err := norm.CallBeforeCreate(ctx, &args, &value); err != nil {
+		return err
+	}
 	return c.affect(ctx, args, value)
 }
 
@@ -115,6 +239,9 @@ type updater[M, A any] struct {
 }
 
 func (u updater[M, A]) Update(ctx context.Context, args A, value M) error {
+	if err := norm.CallBeforeUpdate(ctx, &args, &value); err != nil {
+		return err
+	}
 	return u.affect(ctx, args, value)
 }
 
@@ -123,26 +250,56 @@ type deleter[M, A any] struct {
 }
 
 func (d deleter[M, A]) Delete(ctx context.Context, args A) error {
+	if err := norm.CallBeforeDelete(ctx, &args); err != nil {
+		return err
+	}
 	var nop M
 	return d.affect(ctx, args, nop)
 }
 
+type restorer[M, A any] struct {
+	writer[M, A]
+}
+
+func (r restorer[M, A]) Restore(ctx context.Context, args A) error {
+	var nop M
+	return r.affect(ctx, args, nop)
+}
+
+type deletedReader[M, A any] struct {
+	r reader[M, A]
+}
+
+func (d deletedReader[M, A]) ReadWithDeleted(ctx context.Context, args A) (value M, err error) {
+	return d.r.Read(ctx, args)
+}
+
 type writer[M, A any] struct {
-	db  *sql.DB
-	tpl string
+	db      DB
+	tpl     string
+	policy  norm.RowsPolicy
+	scope   scopeOptions
+	timeout opTimeout
 }
 
-func (w writer[M, A]) affect(ctx context.Context, args A, value M) error {
-	pr := newPreparer(txValue(ctx), w.db)
-	if err := w.exec(ctx, pr, args, value); err != nil {
-		return err
-	}
+func (w writer[M, A]) check(op string) tplCheck {
+	return checkOf[ma[M, A]](op, w.tpl)
+}
 
-	return nil
+func (w writer[M, A]) affect(ctx context.Context, args A, value M) error {
+	return w.timeout.run(ctx, func(ctx context.Context) error {
+		pr := newPreparer(txValue(ctx), w.db)
+		return w.exec(ctx, pr, args, value)
+	})
 }
 
 func (w writer[M, A]) exec(ctx context.Context, pr preparer, args A, value M) error {
-	stmtRaw, stmtA, err := tq.Compile(w.tpl, ma[M, A]{M: value, A: args})
+	c, err := w.scope.bind(ctx)
+	if err != nil {
+		return err
+	}
+
+	stmtRaw, stmtA, err := tq.Compile(w.tpl, ma[M, A]{M: value, A: args, C: c})
 	if err != nil {
 		return fmt.Errorf("compile query template: %w", err)
 	}
@@ -158,47 +315,63 @@ func (w writer[M, A]) exec(ctx context.Context, pr preparer, args A, value M) er
 	}
 
 	n, err := res.RowsAffected()
-	if err == nil && n <= 0 {
-		return norm.ErrNotAffected
+	if err != nil {
+		if w.policy.IsAny() {
+			return nil
+		}
+		return fmt.Errorf("get rows affected: %w", err)
 	}
 
-	return nil
+	norm.ReportRowsAffected(ctx, n)
+
+	return w.policy.Check(n)
 }
 
 type reader[M, A any] struct {
-	db        *sql.DB
-	tpl       string
-	scanSlice bool
+	db      DB
+	tpl     string
+	mode    scanMode
+	scan    scanOptions
+	scope   scopeOptions
+	timeout opTimeout
 }
 
 func (r reader[M, A]) Read(ctx context.Context, args A) (value M, err error) {
-	pr := newPreparer(txValue(ctx), r.db)
+	err = r.timeout.run(ctx, func(ctx context.Context) error {
+		pr := newPreparer(txValue(ctx), readDB(ctx, r.db))
 
-	rows, err := r.query(ctx, pr, args)
+		rows, err := r.query(ctx, pr, args)
+		if err != nil {
+			return err
+		}
+
+		return scanInto(r.mode, r.scan, &value, rows)
+	})
 	if err != nil {
 		return value, err
 	}
 
-	if r.scanSlice {
-		err = scan.RowsStrict(&value, rows)
-		if err != nil {
-			return value, fmt.Errorf("scan rows: %w", err)
-		}
-	} else {
-		err = scan.RowStrict(&value, rows)
-		if errors.Is(err, sql.ErrNoRows) {
-			return value, norm.ErrNotFound
-		}
-		if err != nil {
-			return value, fmt.Errorf("scan one row: %w", err)
-		}
+	if err := norm.CallAfterRead(ctx, &value); err != nil {
+		return value, err
 	}
 
 	return value, nil
 }
 
+func (r reader[M, A]) check(op string) tplCheck {
+	return checkOf[a[A]](op, r.tpl)
+}
+
 func (r reader[M, A]) query(ctx context.Context, pr preparer, args A) (rows *sql.Rows, err error) {
-	stmtRaw, stmtA, err := tq.Compile(r.tpl, a[A]{A: args})
+	c, err := r.scope.bind(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return query(ctx, pr, r.tpl, a[A]{A: args, C: c})
+}
+
+func query(ctx context.Context, pr preparer, tpl string, data any) (rows *sql.Rows, err error) {
+	stmtRaw, stmtA, err := tq.Compile(tpl, data)
 	if err != nil {
 		return nil, fmt.Errorf("compile query template: %w", err)
 	}
@@ -216,12 +389,7 @@ func (r reader[M, A]) query(ctx context.Context, pr preparer, args A) (rows *sql
 	return rows, nil
 }
 
-func isSlice[M any]() bool {
-	var v M
-	return reflect.TypeOf(v).Kind() == reflect.Slice
-}
-
-func newPreparer(tx *sql.Tx, db *sql.DB) (pr preparer) {
+func newPreparer(tx *sql.Tx, db DB) (pr preparer) {
 	if tx != nil {
 		pr = tx
 	} else {
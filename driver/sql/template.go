@@ -0,0 +1,380 @@
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/VauntDev/tqla"
+)
+
+const paramFunc = "_sql_param_"
+
+// compiler executes query templates. Every action result becomes a query parameter,
+// except SQL fragments made by the template functions.
+type compiler struct {
+	placeholder tqla.Placeholder
+	quote       Quoter
+}
+
+// Quoter quotes identifiers made by the template functions and the Bulk loader.
+type Quoter func(ident string) string
+
+var (
+	// DoubleQuotes quotes identifiers in the ANSI style of Postgres and SQLite: "name".
+	DoubleQuotes Quoter = func(ident string) string {
+		return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+	}
+
+	// Backticks quotes identifiers in the MySQL style: `name`.
+	Backticks Quoter = func(ident string) string {
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	}
+)
+
+// Compile executes query template with data and returns query with placeholders formatted by p and its arguments.
+// Templates syntax and functions are the same as of the package objects, so other drivers can share them.
+// Identifiers are quoted by DoubleQuotes.
+func Compile(p tqla.Placeholder, tpl string, data any) (query string, args []any, err error) {
+	return compiler{placeholder: p, quote: DoubleQuotes}.Compile(tpl, data)
+}
+
+// Compile executes the template and returns query with placeholders and its arguments.
+func (c compiler) Compile(statement string, data any) (string, []any, error) {
+	var args []any
+
+	funcs := templateFuncs(c.quote)
+	funcs[paramFunc] = func(v any) (string, error) {
+		if f, ok := v.(fragment); ok {
+			switch {
+			case f.nilList:
+				return "", fmt.Errorf("in: nil list")
+			case f.emptyList:
+				return "", fmt.Errorf("in: empty list")
+			}
+			args = append(args, f.args...)
+			return f.sql, nil
+		}
+		args = append(args, v)
+		return "?", nil
+	}
+
+	t, err := template.New("query").Funcs(funcs).Parse(statement)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, tt := range t.Templates() {
+		appendParam(tt.Tree, tt.Tree.Root)
+	}
+
+	var b bytes.Buffer
+
+	if err := t.Execute(&b, data); err != nil {
+		return "", nil, err
+	}
+
+	stmt, err := c.placeholder.Format(b.String())
+	if err != nil {
+		return "", nil, err
+	}
+
+	return stmt, args, nil
+}
+
+// appendParam pipes every action result into the parameter function.
+func appendParam(t *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			appendParam(t, child)
+		}
+	case *parse.IfNode:
+		appendParam(t, n.List)
+		appendParam(t, n.ElseList)
+	case *parse.RangeNode:
+		appendParam(t, n.List)
+		appendParam(t, n.ElseList)
+	case *parse.WithNode:
+		appendParam(t, n.List)
+		appendParam(t, n.ElseList)
+	case *parse.ActionNode:
+		pipe := n.Pipe
+		if len(pipe.Decl) > 0 || len(pipe.Cmds) == 0 {
+			return
+		}
+		last := pipe.Cmds[len(pipe.Cmds)-1]
+		pipe.Cmds = append(pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      last.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(paramFunc).SetTree(t).SetPos(last.Pos)},
+		})
+	}
+}
+
+// fragment is a piece of SQL with "?" placeholders for its arguments.
+type fragment struct {
+	sql  string
+	args []any
+	// emptyList marks list of the empty slice made by "in",
+	// it can't be rendered, but makes conditions FALSE.
+	emptyList bool
+	// nilList marks list of the nil pointer to slice made by "in",
+	// it can't be rendered, but makes conditions empty.
+	nilList bool
+}
+
+func (f fragment) empty() bool {
+	return f.sql == ""
+}
+
+// templateFuncs returns functions available in query templates:
+//
+//	in      - {{ in .A.IDs }} expands slice or pointer to slice to the list of placeholders "(?, ?, ?)",
+//	          an empty slice fails the query, since neither IN nor NOT IN of an empty list is valid SQL,
+//	          but it makes condition FALSE when passed to cond, so an empty filter matches nothing;
+//	          pass a nil pointer to slice to make the condition empty instead;
+//	ident   - {{ ident .A.Column "id" "name" }} quotes identifier if it is in the allowlist;
+//	orderBy - {{ orderBy .A.Sort "id" "created_at" }} makes ORDER BY clause from comma separated
+//	          allowlisted columns, "-" prefix means descending order, empty sort makes nothing;
+//	cond    - {{ cond "\"name\" = ?" .A.Name }} makes condition, it is empty when any argument is
+//	          nil, nil pointer or empty string, slice or map; results of other functions are
+//	          substituted as is, e.g. {{ cond "\"id\" IN ?" (in .A.IDs) }};
+//	all     - joins non-empty conditions by AND, it's not named "and" to keep short-circuit
+//	          evaluation of the builtin, e.g. {{ if and .A.Parent .A.Parent.ID }};
+//	where   - {{ where (all (cond ...) (cond ...)) }} makes WHERE clause from non-empty condition;
+//	values  - {{ values .A.Rows "ID" "Name" }} makes VALUES list of the slice items fields.
+func templateFuncs(quote Quoter) template.FuncMap {
+	return template.FuncMap{
+		"in": inFunc,
+		"ident": func(name string, allowed ...string) (fragment, error) {
+			return identFunc(quote, name, allowed...)
+		},
+		"orderBy": func(sort string, allowed ...string) (fragment, error) {
+			return orderByFunc(quote, sort, allowed...)
+		},
+		"cond":   condFunc,
+		"all":    allFunc,
+		"where":  whereFunc,
+		"values": valuesFunc,
+	}
+}
+
+func inFunc(list any) (fragment, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return fragment{nilList: true}, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fragment{}, fmt.Errorf("in: %T is not a slice", list)
+	}
+
+	if v.Len() == 0 {
+		return fragment{emptyList: true}, nil
+	}
+
+	args := make([]any, v.Len())
+	for i := range args {
+		args[i] = v.Index(i).Interface()
+	}
+
+	return fragment{
+		sql:  "(" + strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ") + ")",
+		args: args,
+	}, nil
+}
+
+func identFunc(quote Quoter, name string, allowed ...string) (fragment, error) {
+	for _, a := range allowed {
+		if a == name {
+			return fragment{sql: quote(name)}, nil
+		}
+	}
+	return fragment{}, fmt.Errorf("ident: %q is not allowed", name)
+}
+
+func orderByFunc(quote Quoter, sort string, allowed ...string) (fragment, error) {
+	if strings.TrimSpace(sort) == "" {
+		return fragment{}, nil
+	}
+
+	var terms []string
+
+	for _, s := range strings.Split(sort, ",") {
+		s = strings.TrimSpace(s)
+
+		order := "ASC"
+		if strings.HasPrefix(s, "-") {
+			s, order = s[1:], "DESC"
+		}
+
+		f, err := identFunc(quote, s, allowed...)
+		if err != nil {
+			return fragment{}, fmt.Errorf("orderBy: %q is not allowed", s)
+		}
+
+		terms = append(terms, f.sql+" "+order)
+	}
+
+	return fragment{sql: "ORDER BY " + strings.Join(terms, ", ")}, nil
+}
+
+func condFunc(sql string, args ...any) (fragment, error) {
+	if n := countPlaceholders(sql); n != len(args) {
+		return fragment{}, fmt.Errorf("cond: %q has %d placeholders, but %d arguments are given", sql, n, len(args))
+	}
+
+	for _, arg := range args {
+		if isEmpty(arg) {
+			return fragment{}, nil
+		}
+	}
+
+	for _, arg := range args {
+		if f, ok := arg.(fragment); ok && f.emptyList {
+			return fragment{sql: "FALSE"}, nil
+		}
+	}
+
+	return splice(sql, args), nil
+}
+
+// splice substitutes placeholders of fragment arguments by their SQL.
+func splice(sql string, args []any) fragment {
+	var (
+		b   strings.Builder
+		out []any
+		n   int
+	)
+
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != '?' {
+			b.WriteByte(sql[i])
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == '?' {
+			b.WriteString("??")
+			i++
+			continue
+		}
+
+		if f, ok := args[n].(fragment); ok {
+			b.WriteString(f.sql)
+			out = append(out, f.args...)
+		} else {
+			b.WriteByte('?')
+			out = append(out, args[n])
+		}
+		n++
+	}
+
+	return fragment{sql: b.String(), args: out}
+}
+
+func allFunc(conds ...fragment) fragment {
+	var (
+		terms []string
+		args  []any
+	)
+
+	for _, f := range conds {
+		if f.empty() {
+			continue
+		}
+		terms = append(terms, "("+f.sql+")")
+		args = append(args, f.args...)
+	}
+
+	return fragment{sql: strings.Join(terms, " AND "), args: args}
+}
+
+func whereFunc(cond fragment) fragment {
+	if cond.empty() {
+		return fragment{}
+	}
+	return fragment{sql: "WHERE " + cond.sql, args: cond.args}
+}
+
+func valuesFunc(rows any, fields ...string) (fragment, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fragment{}, fmt.Errorf("values: %T is not a slice", rows)
+	}
+
+	if v.Len() == 0 {
+		return fragment{}, fmt.Errorf("values: no rows")
+	}
+
+	if len(fields) == 0 {
+		return fragment{}, fmt.Errorf("values: no fields")
+	}
+
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(fields)), ", ") + ")"
+	terms := make([]string, v.Len())
+	args := make([]any, 0, v.Len()*len(fields))
+
+	for i := 0; i < v.Len(); i++ {
+		item := reflect.Indirect(v.Index(i))
+		if item.Kind() != reflect.Struct {
+			return fragment{}, fmt.Errorf("values: %s is not a struct", item.Type())
+		}
+
+		for _, name := range fields {
+			f := item.FieldByName(name)
+			if !f.IsValid() {
+				return fragment{}, fmt.Errorf("values: unknown field %s in type %s", name, item.Type())
+			}
+			args = append(args, f.Interface())
+		}
+
+		terms[i] = row
+	}
+
+	return fragment{sql: "VALUES " + strings.Join(terms, ", "), args: args}, nil
+}
+
+// countPlaceholders counts "?" ignoring escaped "??".
+func countPlaceholders(sql string) (n int) {
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != '?' {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == '?' {
+			i++
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	if f, ok := v.(fragment); ok {
+		return f.nilList
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	default:
+		return false
+	}
+}
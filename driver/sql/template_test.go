@@ -0,0 +1,202 @@
+package sql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VauntDev/tqla"
+)
+
+type templateRow struct {
+	ID   string
+	Name string
+}
+
+type templateArgs struct {
+	ID     string
+	IDs    []string
+	OptIDs *[]string
+	Name   string
+	Age    *int
+	Column string
+	Sort   string
+	Rows   []templateRow
+	Parent *templateRow
+}
+
+func TestCompiler_Compile(t *testing.T) {
+	age := 18
+
+	tests := []struct {
+		name     string
+		tpl      string
+		args     templateArgs
+		quote    Quoter
+		wantSQL  string
+		wantArgs []any
+		wantErr  string
+	}{
+		{
+			name:     "plain",
+			tpl:      `SELECT * FROM "t" WHERE "id" = {{ .A.ID }} AND "name" = {{ .A.Name }}`,
+			args:     templateArgs{ID: "1", Name: "n"},
+			wantSQL:  `SELECT * FROM "t" WHERE "id" = $1 AND "name" = $2`,
+			wantArgs: []any{"1", "n"},
+		},
+		{
+			name:     "in",
+			tpl:      `SELECT * FROM "t" WHERE "id" IN {{ in .A.IDs }} AND "name" = {{ .A.Name }}`,
+			args:     templateArgs{IDs: []string{"1", "2", "3"}, Name: "n"},
+			wantSQL:  `SELECT * FROM "t" WHERE "id" IN ($1, $2, $3) AND "name" = $4`,
+			wantArgs: []any{"1", "2", "3", "n"},
+		},
+		{
+			name:    "in empty",
+			tpl:     `SELECT * FROM "t" WHERE "id" NOT IN {{ in .A.IDs }}`,
+			wantErr: "in: empty list",
+		},
+		{
+			name:    "ident",
+			tpl:     `SELECT {{ ident .A.Column "id" "name" }} FROM "t"`,
+			args:    templateArgs{Column: "name"},
+			wantSQL: `SELECT "name" FROM "t"`,
+		},
+		{
+			name:    "ident backticks",
+			tpl:     `SELECT {{ ident .A.Column "id" "name" }} FROM t {{ orderBy .A.Sort "id" }}`,
+			args:    templateArgs{Column: "name", Sort: "-id"},
+			quote:   Backticks,
+			wantSQL: "SELECT `name` FROM t ORDER BY `id` DESC",
+		},
+		{
+			name:    "ident not allowed",
+			tpl:     `SELECT {{ ident .A.Column "id" "name" }} FROM "t"`,
+			args:    templateArgs{Column: `name"; DROP TABLE "t`},
+			wantErr: "is not allowed",
+		},
+		{
+			name:    "order by",
+			tpl:     `SELECT * FROM "t" {{ orderBy .A.Sort "id" "name" }}`,
+			args:    templateArgs{Sort: "-name, id"},
+			wantSQL: `SELECT * FROM "t" ORDER BY "name" DESC, "id" ASC`,
+		},
+		{
+			name:    "order by empty",
+			tpl:     `SELECT * FROM "t" {{ orderBy .A.Sort "id" "name" }}`,
+			wantSQL: `SELECT * FROM "t"`,
+		},
+		{
+			name:    "order by not allowed",
+			tpl:     `SELECT * FROM "t" {{ orderBy .A.Sort "id" }}`,
+			args:    templateArgs{Sort: "name"},
+			wantErr: "is not allowed",
+		},
+		{
+			name: "where",
+			tpl: `SELECT * FROM "t" {{ where (all
+				(cond "\"name\" = ?" .A.Name)
+				(cond "\"age\" >= ?" .A.Age)
+				(cond "\"deleted_at\" IS NULL")
+			) }}`,
+			args:     templateArgs{Name: "n", Age: &age},
+			wantSQL:  `SELECT * FROM "t" WHERE ("name" = $1) AND ("age" >= $2) AND ("deleted_at" IS NULL)`,
+			wantArgs: []any{"n", &age},
+		},
+		{
+			name:     "where drops empty conditions",
+			tpl:      `SELECT * FROM "t" {{ where (all (cond "\"name\" = ?" .A.Name) (cond "\"id\" IN ?" (in .A.IDs))) }}`,
+			args:     templateArgs{IDs: []string{"1"}},
+			wantSQL:  `SELECT * FROM "t" WHERE ("id" IN ($1))`,
+			wantArgs: []any{"1"},
+		},
+		{
+			name:    "where empty list is false",
+			tpl:     `SELECT * FROM "t" {{ where (all (cond "\"name\" = ?" .A.Name) (cond "\"id\" IN ?" (in .A.IDs))) }}`,
+			args:    templateArgs{IDs: []string{}},
+			wantSQL: `SELECT * FROM "t" WHERE (FALSE)`,
+		},
+		{
+			name:     "where optional list",
+			tpl:      `SELECT * FROM "t" {{ where (all (cond "\"name\" = ?" .A.Name) (cond "\"id\" IN ?" (in .A.OptIDs))) }}`,
+			args:     templateArgs{Name: "n"},
+			wantSQL:  `SELECT * FROM "t" WHERE ("name" = $1)`,
+			wantArgs: []any{"n"},
+		},
+		{
+			name:     "in pointer",
+			tpl:      `SELECT * FROM "t" WHERE "id" IN {{ in .A.OptIDs }}`,
+			args:     templateArgs{OptIDs: &[]string{"1"}},
+			wantSQL:  `SELECT * FROM "t" WHERE "id" IN ($1)`,
+			wantArgs: []any{"1"},
+		},
+		{
+			name:    "in nil",
+			tpl:     `SELECT * FROM "t" WHERE "id" IN {{ in .A.OptIDs }}`,
+			wantErr: "in: nil list",
+		},
+		{
+			name:    "where without conditions",
+			tpl:     `SELECT * FROM "t" {{ where (all (cond "\"name\" = ?" .A.Name) (cond "\"age\" >= ?" .A.Age)) }}`,
+			wantSQL: `SELECT * FROM "t"`,
+		},
+		{
+			name:    "cond arguments mismatch",
+			tpl:     `{{ cond "\"name\" = ? AND \"id\" = ?" .A.Name }}`,
+			wantErr: "2 placeholders, but 1 arguments",
+		},
+		{
+			name:     "builtin and",
+			tpl:      `SELECT * FROM "t" {{ if and .A.Name .A.ID }}WHERE "id" = {{ .A.ID }}{{ end }}`,
+			args:     templateArgs{ID: "1", Name: "n"},
+			wantSQL:  `SELECT * FROM "t" WHERE "id" = $1`,
+			wantArgs: []any{"1"},
+		},
+		{
+			name:    "builtin and short-circuits",
+			tpl:     `SELECT * FROM "t" {{ if and .A.Parent .A.Parent.ID }}WHERE "parent_id" = {{ .A.Parent.ID }}{{ end }}`,
+			wantSQL: `SELECT * FROM "t"`,
+		},
+		{
+			name: "values",
+			tpl:  `INSERT INTO "t" ("id", "name") {{ values .A.Rows "ID" "Name" }}`,
+			args: templateArgs{Rows: []templateRow{
+				{ID: "1", Name: "a"},
+				{ID: "2", Name: "b"},
+			}},
+			wantSQL:  `INSERT INTO "t" ("id", "name") VALUES ($1, $2), ($3, $4)`,
+			wantArgs: []any{"1", "a", "2", "b"},
+		},
+		{
+			name:    "values without rows",
+			tpl:     `INSERT INTO "t" ("id", "name") {{ values .A.Rows "ID" "Name" }}`,
+			wantErr: "no rows",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := compiler{placeholder: tqla.Dollar, quote: DoubleQuotes}
+			if tt.quote != nil {
+				c.quote = tt.quote
+			}
+
+			gotSQL, gotArgs, err := c.Compile(tt.tpl, a[templateArgs]{A: tt.args})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Compile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("Compile() sql = %q, want %q", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("Compile() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
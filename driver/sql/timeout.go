@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/WinPooh32/norm"
+)
+
+// queryCanceled is SQLSTATE of statements canceled by statement_timeout.
+const queryCanceled = "57014"
+
+// opTimeout is the default timeout of an operation.
+type opTimeout struct {
+	op        norm.Op
+	timeout   time.Duration
+	statement bool
+}
+
+// run calls fn with the context limited by the timeout, unless the context has an earlier deadline.
+// Deadline errors are reported as *norm.TimeoutError.
+func (t opTimeout) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	var timeout time.Duration
+
+	if t.timeout > 0 {
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > t.timeout {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, t.timeout)
+			defer cancel()
+
+			timeout = t.timeout
+		}
+	}
+
+	err := t.setLocal(ctx)
+	if err == nil {
+		err = fn(ctx)
+	}
+
+	if err != nil && (errors.Is(ctx.Err(), context.DeadlineExceeded) || isStatementTimeout(err)) {
+		return &norm.TimeoutError{Op: t.op, Timeout: timeout, Err: err}
+	}
+
+	return err
+}
+
+// setLocal sets statement_timeout of the context transaction to the operation timeout.
+func (t opTimeout) setLocal(ctx context.Context) error {
+	if !t.statement || t.timeout <= 0 {
+		return nil
+	}
+
+	tx := txValue(ctx)
+	if tx == nil {
+		return nil
+	}
+
+	stmt, err := tq.placeholder.Format("SELECT set_config('statement_timeout', ?, true)")
+	if err != nil {
+		return fmt.Errorf("format set_config query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, stmt, fmt.Sprint(t.timeout.Milliseconds())); err != nil {
+		return fmt.Errorf("set statement_timeout: %w", err)
+	}
+
+	return nil
+}
+
+func isStatementTimeout(err error) bool {
+	var state interface{ SQLState() string }
+	return errors.As(err, &state) && state.SQLState() == queryCanceled
+}
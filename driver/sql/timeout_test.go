@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/norm"
+)
+
+type stateError string
+
+func (e stateError) Error() string {
+	return "state " + string(e)
+}
+
+func (e stateError) SQLState() string {
+	return string(e)
+}
+
+func TestOpTimeout_Run(t *testing.T) {
+	wait := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ot := opTimeout{op: norm.OpRead, timeout: 10 * time.Millisecond}
+
+	err := ot.run(context.Background(), wait)
+
+	var terr *norm.TimeoutError
+	if !errors.As(err, &terr) {
+		t.Fatalf("run() error = %v, want *norm.TimeoutError", err)
+	}
+	if terr.Op != norm.OpRead || terr.Timeout != ot.timeout {
+		t.Errorf("run() error = %+v", terr)
+	}
+	if !errors.Is(err, norm.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("run() error = %v, want norm.ErrTimeout and context.DeadlineExceeded", err)
+	}
+}
+
+func TestOpTimeout_Run_EarlierDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+
+	ot := opTimeout{op: norm.OpRead, timeout: time.Hour}
+
+	err := ot.run(ctx, func(ctx context.Context) error {
+		if got, _ := ctx.Deadline(); !got.Equal(deadline) {
+			t.Errorf("deadline = %v, want %v", got, deadline)
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var terr *norm.TimeoutError
+	if !errors.As(err, &terr) {
+		t.Fatalf("run() error = %v, want *norm.TimeoutError", err)
+	}
+	if terr.Timeout != 0 {
+		t.Errorf("Timeout = %v, want 0", terr.Timeout)
+	}
+}
+
+func TestOpTimeout_Run_Errors(t *testing.T) {
+	errOther := errors.New("other")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantTimeout bool
+	}{
+		{name: "nil", err: nil},
+		{name: "other", err: errOther},
+		{name: "other state", err: stateError("23505")},
+		{name: "statement timeout", err: stateError(queryCanceled), wantTimeout: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := opTimeout{op: norm.OpUpdate}.run(context.Background(), func(ctx context.Context) error {
+				return tt.err
+			})
+
+			if got := errors.Is(err, norm.ErrTimeout); got != tt.wantTimeout {
+				t.Errorf("errors.Is(%v, norm.ErrTimeout) = %v, want %v", err, got, tt.wantTimeout)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("run() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
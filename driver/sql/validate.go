@@ -0,0 +1,245 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Must panics when templates of v are not valid. It's intended for initialization of package level variables:
+//
+//	var users = normsql.Must(normsql.NewView[[]User, Args](db, `...`))
+func Must[T interface{ Validate() error }](v T) T {
+	if err := v.Validate(); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// tplCheck is a template with type of its data.
+type tplCheck struct {
+	op   string
+	tpl  string
+	data reflect.Type
+}
+
+func checkOf[T any](op, tpl string) tplCheck {
+	return tplCheck{
+		op:   op,
+		tpl:  tpl,
+		data: reflect.TypeOf((*T)(nil)).Elem(),
+	}
+}
+
+// validate parses templates and checks that referenced fields exist.
+// Empty templates are skipped.
+func validate(checks ...tplCheck) error {
+	for _, c := range checks {
+		if c.tpl == "" {
+			continue
+		}
+
+		t, err := template.New(c.op).Funcs(templateFuncs(tq.quote)).Parse(c.tpl)
+		if err != nil {
+			return fmt.Errorf("parse %s template: %w", c.op, err)
+		}
+
+		v := fieldsValidator{tree: t.Tree, root: c.data}
+
+		if err := v.walk(t.Tree.Root, c.data); err != nil {
+			return fmt.Errorf("validate %s template: %w", c.op, err)
+		}
+	}
+
+	return nil
+}
+
+// validateDB validates templates and prepares their statements on the database,
+// so the database checks syntax of the queries. Templates are executed against zero values.
+func validateDB(ctx context.Context, db DB, checks ...tplCheck) error {
+	if err := validate(checks...); err != nil {
+		return err
+	}
+
+	for _, c := range checks {
+		if c.tpl == "" {
+			continue
+		}
+
+		stmtRaw, _, err := tq.Compile(c.tpl, deepZero(c.data).Interface())
+		if err != nil {
+			return fmt.Errorf("compile %s template: %w", c.op, err)
+		}
+
+		stmt, err := db.PrepareContext(ctx, stmtRaw)
+		if err != nil {
+			return fmt.Errorf("prepare %s query: %w", c.op, err)
+		}
+
+		if err := stmt.Close(); err != nil {
+			return fmt.Errorf("close %s statement: %w", c.op, err)
+		}
+	}
+
+	return nil
+}
+
+type fieldsValidator struct {
+	tree *parse.Tree
+	root reflect.Type
+}
+
+// walk checks fields of the node. The dot is nil when its type is unknown, e.g. inside range.
+func (v fieldsValidator) walk(node parse.Node, dot reflect.Type) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := v.walk(child, dot); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return v.pipe(n.Pipe, dot)
+	case *parse.TemplateNode:
+		return v.pipe(n.Pipe, dot)
+	case *parse.IfNode:
+		return v.branch(&n.BranchNode, dot, dot)
+	case *parse.RangeNode:
+		return v.branch(&n.BranchNode, dot, nil)
+	case *parse.WithNode:
+		return v.branch(&n.BranchNode, dot, nil)
+	}
+	return nil
+}
+
+func (v fieldsValidator) branch(n *parse.BranchNode, dot, listDot reflect.Type) error {
+	if err := v.pipe(n.Pipe, dot); err != nil {
+		return err
+	}
+	if err := v.walk(n.List, listDot); err != nil {
+		return err
+	}
+	return v.walk(n.ElseList, dot)
+}
+
+func (v fieldsValidator) pipe(pipe *parse.PipeNode, dot reflect.Type) error {
+	if pipe == nil {
+		return nil
+	}
+
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			var err error
+
+			switch n := arg.(type) {
+			case *parse.FieldNode:
+				err = v.resolve(n, dot, ".", n.Ident)
+			case *parse.VariableNode:
+				if n.Ident[0] == "$" {
+					err = v.resolve(n, v.root, "$.", n.Ident[1:])
+				}
+			case *parse.PipeNode:
+				err = v.pipe(n, dot)
+			case *parse.ChainNode:
+				if p, ok := n.Node.(*parse.PipeNode); ok {
+					err = v.pipe(p, dot)
+				}
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v fieldsValidator) resolve(node parse.Node, t reflect.Type, prefix string, idents []string) error {
+	for i, id := range idents {
+		if t == nil {
+			return nil
+		}
+
+		if m, ok := method(t, id); ok {
+			if m.Type.NumOut() == 0 {
+				return nil
+			}
+			t = m.Type.Out(0)
+			continue
+		}
+
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			f, ok := t.FieldByName(id)
+			if ok && f.IsExported() {
+				t = f.Type
+				continue
+			}
+		case reflect.Map:
+			t = t.Elem()
+			continue
+		case reflect.Interface:
+			return nil
+		}
+
+		location, _ := v.tree.ErrorContext(node)
+
+		return fmt.Errorf("%s: unknown field %s%s in type %s",
+			location, prefix, strings.Join(idents[:i+1], "."), t)
+	}
+
+	return nil
+}
+
+func method(t reflect.Type, name string) (reflect.Method, bool) {
+	if t.Kind() != reflect.Interface && t.Kind() != reflect.Pointer {
+		t = reflect.PointerTo(t)
+	}
+	return t.MethodByName(name)
+}
+
+// deepZero makes zero value of the type with allocated pointers to structs,
+// so template execution doesn't stop at nil pointers.
+func deepZero(t reflect.Type) reflect.Value {
+	return deepZeroSeen(t, map[reflect.Type]bool{})
+}
+
+func deepZeroSeen(t reflect.Type, seen map[reflect.Type]bool) reflect.Value {
+	v := reflect.New(t).Elem()
+
+	if t.Kind() != reflect.Struct || seen[t] {
+		return v
+	}
+
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		switch {
+		case f.Type.Kind() == reflect.Struct:
+			v.Field(i).Set(deepZeroSeen(f.Type, seen))
+		case f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct && !seen[f.Type.Elem()]:
+			p := reflect.New(f.Type.Elem())
+			p.Elem().Set(deepZeroSeen(f.Type.Elem(), seen))
+			v.Field(i).Set(p)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,115 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+)
+
+type validateModel struct {
+	FieldA string
+	Nested *struct {
+		FieldB int
+	}
+	Extra map[string]any
+}
+
+func (validateModel) Upper() string { return "" }
+
+type validateArgs struct {
+	ID  string
+	IDs []string
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   tplCheck
+		wantErr string
+	}{
+		{
+			name:  "empty",
+			check: checkOf[ma[validateModel, validateArgs]]("create", ``),
+		},
+		{
+			name: "valid",
+			check: checkOf[ma[validateModel, validateArgs]]("create", `
+INSERT INTO "t" VALUES ({{ .A.ID }}, {{ .M.FieldA }}, {{ .M.Nested.FieldB }}, {{ .M.Extra.key }}, {{ .M.Upper }})
+{{ range .A.IDs }} {{ .Unknown }} {{ $.A.ID }} {{ end }}`),
+		},
+		{
+			name:    "syntax error",
+			check:   checkOf[a[validateArgs]]("read", `SELECT {{ .A.ID `),
+			wantErr: "parse read template",
+		},
+		{
+			name: "unknown field",
+			check: checkOf[ma[validateModel, validateArgs]]("update", `
+UPDATE "t"
+SET "a" = {{ .M.FieldX }}`),
+			wantErr: "update:3:15: unknown field .M.FieldX",
+		},
+		{
+			name:    "unknown nested field",
+			check:   checkOf[ma[validateModel, validateArgs]]("update", `{{ .M.Nested.FieldX }}`),
+			wantErr: "unknown field .M.Nested.FieldX",
+		},
+		{
+			name:    "unknown field in not taken branch",
+			check:   checkOf[a[validateArgs]]("read", `{{ if .A.ID }} {{ .A.Name }} {{ end }}`),
+			wantErr: "unknown field .A.Name",
+		},
+		{
+			name:    "unknown field of root variable",
+			check:   checkOf[a[validateArgs]]("read", `{{ range .A.IDs }} {{ $.A.Name }} {{ end }}`),
+			wantErr: "unknown field $.A.Name",
+		},
+		{
+			name:    "field of scalar",
+			check:   checkOf[a[validateArgs]]("read", `{{ .A.ID.Value }}`),
+			wantErr: "unknown field .A.ID.Value in type string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(tt.check)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeepZero(t *testing.T) {
+	type node struct {
+		Next  *node
+		Model *validateModel
+	}
+
+	v := deepZero(checkOf[node]("", "").data).Interface().(node)
+
+	if v.Model == nil || v.Model.Nested == nil {
+		t.Errorf("deepZero() = %+v, want allocated pointers", v)
+	}
+
+	if v.Next != nil {
+		t.Errorf("deepZero() = %+v, want recursive pointer to be nil", v)
+	}
+}
+
+func TestMust(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Must() didn't panic")
+		}
+	}()
+
+	Must(NewView[validateModel, validateArgs](nil, `SELECT {{ .A.Unknown }}`))
+}
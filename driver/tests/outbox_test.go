@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/WinPooh32/norm/outbox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutbox_Postgres(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	box := outbox.New("outbox", outbox.Postgres)
+
+	if _, err := db.Exec(`DROP TABLE IF EXISTS "outbox";`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(box.Schema()); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := normsql.NewObject[ModelShort, Args](setupQueries())
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	ctx := normsql.WithTransaction(context.Background(), tx)
+
+	for i := 0; i < 50; i++ {
+		if err := box.Enqueue(ctx, outbox.Event{Topic: "test", Key: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := obj.Delete(ctx, Args{ID: "id01"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu        sync.Mutex
+		published = map[string]int{}
+	)
+
+	pub := outbox.PublisherFunc(func(ctx context.Context, e outbox.Event) error {
+		mu.Lock()
+		published[e.Key]++
+		mu.Unlock()
+		return nil
+	})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		relay := outbox.NewRelay(db, box, pub, outbox.WithBatchSize(5))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				n, err := relay.Poll(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if n == 0 {
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, published, 50)
+	for key, n := range published {
+		assert.Equal(t, 1, n, "event %s", key)
+	}
+}
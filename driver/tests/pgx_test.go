@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/norm"
+	normpgx "github.com/WinPooh32/norm/driver/pgx"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPgxPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), databaseUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func setupPgxObject(db normpgx.DB) normpgx.Object[ModelShort, Args] {
+	_, c, r, u, d := setupQueries()
+	return normpgx.NewObject[ModelShort, Args](db, c, r, u, d)
+}
+
+func TestPgxObject_CRUD(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	var obj norm.Object[ModelShort, Args] = setupPgxObject(newPgxPool(t))
+
+	ctx := context.Background()
+	ts := time.Date(2001, 9, 28, 23, 0, 0, 0, time.UTC)
+	args := Args{ID: "id03", CreatedAt: ts, UpdatedAt: ts}
+	want := ModelShort{FieldA: "x", FieldB: "y", FieldC: 7}
+
+	if err := obj.Create(ctx, args, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := obj.Read(ctx, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, got)
+
+	want.FieldC = 8
+	if err := obj.Update(ctx, args, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = obj.Read(ctx, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, got)
+
+	if err := obj.Delete(ctx, args); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = obj.Read(ctx, args)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+
+	err = obj.Delete(ctx, args)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotAffected)
+	}
+}
+
+func TestPgxObject_WithTransaction(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := newPgxPool(t)
+	obj := setupPgxObject(pool)
+
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(context.Background())
+
+	ctxTx := normpgx.WithTransaction(context.Background(), tx)
+
+	if err := obj.Delete(ctxTx, Args{ID: "id01"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = obj.Read(ctxTx, Args{ID: "id01"})
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+
+	_, err = obj.Read(context.Background(), Args{ID: "id01"})
+	assert.NoError(t, err)
+
+	_, err = setupPgxObject(tx).Read(context.Background(), Args{ID: "id01"})
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+}
+
+func TestPgxView_Read(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := newPgxPool(t)
+	ctx := context.Background()
+	args := FilterIDs{IDs: []string{"id01", "id02"}}
+
+	models, err := normpgx.NewView[[]Model, FilterIDs](pool,
+		`SELECT * FROM "tests" WHERE "id" = ANY({{ .A.IDs }}) ORDER BY "id";`,
+	).Read(ctx, args)
+	if assert.NoError(t, err) && assert.Len(t, models, 2) {
+		assert.Equal(t, "id01", models[0].ID)
+		assert.Equal(t, 4321, models[1].FieldC)
+	}
+
+	ids, err := normpgx.NewView[[]string, FilterIDs](pool,
+		`SELECT "id" FROM "tests" WHERE "id" = ANY({{ .A.IDs }}) ORDER BY "id";`,
+	).Read(ctx, args)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"id01", "id02"}, ids)
+	}
+
+	row, err := normpgx.NewView[map[string]any, FilterIDs](pool,
+		`SELECT "id", "field_c" FROM "tests" WHERE "id" = ANY({{ .A.IDs }}) ORDER BY "id";`,
+	).Read(ctx, args)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]any{"id": "id01", "field_c": int32(1234)}, row)
+	}
+
+	array, err := normpgx.NewView[[]string, FilterIDs](pool,
+		`SELECT array_agg("id" ORDER BY "id") FROM "tests" WHERE "id" = ANY({{ .A.IDs }});`,
+	).Read(ctx, args)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"id01", "id02"}, array)
+	}
+
+	snake, err := normpgx.NewView[[]struct{ ID, FieldA string }, FilterIDs](pool,
+		`SELECT "id", "field_a" FROM "tests" WHERE "id" = ANY({{ .A.IDs }}) ORDER BY "id";`,
+		normpgx.WithSnakeCase(),
+	).Read(ctx, args)
+	if assert.NoError(t, err) && assert.Len(t, snake, 2) {
+		assert.Equal(t, "aaaa", snake[1].FieldA)
+	}
+
+	empty, err := normpgx.NewView[[]Model, FilterIDs](pool,
+		`SELECT * FROM "tests" WHERE "id" = ANY({{ .A.IDs }});`,
+	).Read(ctx, FilterIDs{IDs: []string{"-1"}})
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestBatch_ReadAll(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := newPgxPool(t)
+
+	var b norm.Batch
+
+	one := norm.Queue[ModelShort, Args](&b, setupPgxObject(pool), Args{ID: "id01"})
+	ids := norm.Queue[[]string, FilterIDs](&b,
+		normpgx.NewView[[]string, FilterIDs](pool, `SELECT "id" FROM "tests" WHERE "id" = ANY({{ .A.IDs }}) ORDER BY "id";`),
+		FilterIDs{IDs: []string{"id01", "id02"}},
+	)
+	missing := norm.Queue[ModelShort, Args](&b, setupPgxObject(pool), Args{ID: "-1"})
+	fallback := norm.Queue[ModelShort, Args](&b, normsql.NewObject[ModelShort, Args](setupQueries()), Args{ID: "id02"})
+
+	err := b.ReadAll(context.Background())
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+
+	assert.NoError(t, one.Err)
+	assert.Equal(t, ModelShort{FieldA: "a", FieldB: "b", FieldC: 1234}, one.Value)
+
+	assert.NoError(t, ids.Err)
+	assert.Equal(t, []string{"id01", "id02"}, ids.Value)
+
+	assert.ErrorIs(t, missing.Err, norm.ErrNotFound)
+
+	assert.NoError(t, fallback.Err)
+	assert.Equal(t, ModelShort{FieldA: "aaaa", FieldB: "bbbb", FieldC: 4321}, fallback.Value)
+}
+
+func TestPgxBulk_Load(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress []int64
+
+	bulk := normpgx.NewBulk[Model](newPgxPool(t), "tests",
+		normpgx.WithProgress(400, func(n int64) { progress = append(progress, n) }),
+	)
+
+	n, err := bulk.Load(context.Background(), normsql.SliceIterator(bulkModels(1000)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int64(1000), n)
+	assert.Equal(t, []int64{400, 800, 1000}, progress)
+	assert.Equal(t, 1002, countTests(t))
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObject_Delete_AffectedRows_Any(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	db, c, r, u, d := setupQueries()
+	obj := normsql.NewObject[ModelShort, Args](db, c, r, u, d, normsql.WithAffectedRows(norm.Any, norm.OpDelete))
+
+	var n int64
+	ctx := norm.WithRowsAffected(context.Background(), &n)
+
+	assert.NoError(t, obj.Delete(ctx, Args{ID: "id01"}))
+	assert.Equal(t, int64(1), n)
+
+	assert.NoError(t, obj.Delete(ctx, Args{ID: "id01"}))
+	assert.Equal(t, int64(0), n)
+
+	err := obj.Update(ctx, Args{ID: "id01"}, ModelShort{})
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotAffected)
+	}
+}
+
+func TestObject_Update_AffectedRows_Exactly(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := normsql.NewPersistentObject[ModelShort, struct{}](db,
+		`INSERT INTO "tests" DEFAULT VALUES;`,
+		`SELECT "field_a", "field_b", "field_c" FROM "tests";`,
+		`UPDATE "tests" SET "field_c" = {{ .M.FieldC }};`,
+		normsql.WithAffectedRows(norm.Exactly(1)),
+	)
+
+	var n int64
+	ctx := norm.WithRowsAffected(context.Background(), &n)
+
+	err := obj.Update(ctx, struct{}{}, ModelShort{FieldC: 1})
+
+	var affected *norm.AffectedError
+	if assert.True(t, errors.As(err, &affected)) {
+		assert.Equal(t, int64(2), affected.Affected)
+		assert.Equal(t, norm.Exactly(1), affected.Policy)
+		assert.NotErrorIs(t, err, norm.ErrNotAffected)
+	}
+
+	assert.Equal(t, int64(2), n)
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func bulkModels(n int) []Model {
+	ts := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	models := make([]Model, n)
+	for i := range models {
+		models[i] = Model{
+			ID:        fmt.Sprintf("bulk%04d", i),
+			FieldA:    "a",
+			FieldB:    "b",
+			FieldC:    i,
+			CreatedAt: ts,
+			UpdatedAt: ts,
+		}
+	}
+
+	return models
+}
+
+func countTests(t *testing.T) (n int) {
+	t.Helper()
+
+	if err := db.QueryRow(`SELECT count(*) FROM "tests";`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
+
+func TestBulk_Load(t *testing.T) {
+	tests := []struct {
+		name     string
+		copier   normsql.Copier
+		progress []int64
+	}{
+		{"copy", normsql.CopyFromStdin, []int64{1000}},
+		{"insert batches", normsql.InsertBatches(300), []int64{600, 900, 1000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := resetDB(t, db); err != nil {
+				t.Fatal(err)
+			}
+
+			var progress []int64
+
+			bulk := normsql.NewBulk[Model](db, "tests",
+				normsql.WithCopier(tt.copier),
+				normsql.WithProgress(400, func(n int64) { progress = append(progress, n) }),
+			)
+
+			n, err := bulk.Load(context.Background(), normsql.SliceIterator(bulkModels(1000)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, int64(1000), n)
+			assert.Equal(t, tt.progress, progress)
+			assert.Equal(t, 1002, countTests(t))
+		})
+	}
+}
+
+func TestBulk_Load_WithTransaction(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	bulk := normsql.NewBulk[Model](db, "tests")
+
+	n, err := bulk.Load(normsql.WithTransaction(context.Background(), tx), normsql.SliceIterator(bulkModels(10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int64(10), n)
+	assert.Equal(t, 2, countTests(t))
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 12, countTests(t))
+}
+
+func TestBulk_Load_Error_Canceled(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	models := bulkModels(100)
+	i := 0
+
+	values := normsql.IteratorFunc[Model](func(ctx context.Context) (Model, error) {
+		if i == 50 {
+			cancel()
+		}
+		if i == len(models) {
+			return Model{}, io.EOF
+		}
+		i++
+		return models[i-1], nil
+	})
+
+	_, err := normsql.NewBulk[Model](db, "tests").Load(ctx, values)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, context.Canceled))
+	}
+
+	assert.Equal(t, 2, countTests(t))
+}
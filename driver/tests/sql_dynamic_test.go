@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestView_Read_Map(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewView[map[string]any, FilterID](db, `
+	SELECT "id", "field_c" FROM "tests" WHERE "id" = {{ .A.ID }};`,
+	)
+
+	got, err := view.Read(context.Background(), FilterID{ID: "id01"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]any{"id": "id01", "field_c": int64(1234)}, got)
+	}
+
+	_, err = view.Read(context.Background(), FilterID{ID: "-1"})
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+}
+
+func TestView_Read_Map_Slice(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewView[[]map[string]any, FilterIDs](db, `
+	SELECT "id", "field_a" FROM "tests" WHERE "id" = ANY( {{ .A.IDs }} ) ORDER BY "id" ASC;`,
+	)
+
+	got, err := view.Read(context.Background(), FilterIDs{IDs: []string{"id01", "id02"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []map[string]any{
+			{"id": "id01", "field_a": "a"},
+			{"id": "id02", "field_a": "aaaa"},
+		}, got)
+	}
+
+	got, err = view.Read(context.Background(), FilterIDs{IDs: []string{"-1"}})
+
+	assert.NoError(t, err)
+	assert.Len(t, got, 0)
+}
+
+func TestView_Read_Row(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewView[[]normsql.Row, struct{}](db, `
+	SELECT "id", "field_c" FROM "tests" ORDER BY "id" ASC;`,
+	)
+
+	got, err := view.Read(context.Background(), struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, got, 2) {
+		row := got[0]
+
+		if assert.Len(t, row.Columns, 2) {
+			assert.Equal(t, "id", row.Columns[0].Name())
+			assert.Equal(t, "TEXT", row.Columns[0].DatabaseTypeName())
+			assert.Equal(t, "INT4", row.Columns[1].DatabaseTypeName())
+		}
+
+		v, ok := row.Get("field_c")
+		assert.True(t, ok)
+		assert.Equal(t, int64(1234), v)
+
+		_, ok = row.Get("unknown")
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+var errReadOnly = errors.New("read only")
+
+type HookArgs struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (a *HookArgs) BeforeCreate(ctx context.Context) error {
+	a.CreatedAt = time.Date(2001, 9, 28, 23, 0, 0, 0, time.UTC)
+	a.UpdatedAt = a.CreatedAt
+	return nil
+}
+
+func (a *HookArgs) BeforeUpdate(ctx context.Context) error {
+	a.UpdatedAt = time.Date(2002, 9, 28, 23, 0, 0, 0, time.UTC)
+	return nil
+}
+
+func (a *HookArgs) BeforeDelete(ctx context.Context) error {
+	if strings.HasPrefix(a.ID, "ro") {
+		return errReadOnly
+	}
+	return nil
+}
+
+type HookModel Model
+
+func (m *HookModel) BeforeCreate(ctx context.Context) error {
+	m.FieldA = strings.ToLower(m.FieldA)
+	return nil
+}
+
+func (m *HookModel) AfterRead(ctx context.Context) error {
+	m.FieldB = strings.ToUpper(m.FieldB)
+	return nil
+}
+
+func TestObject_Hooks(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	db, c, r, u, d := setupQueries()
+	obj := normsql.NewObject[HookModel, HookArgs](db, c, r, u, d)
+	ctx := context.Background()
+
+	err := obj.Create(ctx, HookArgs{ID: "id03"}, HookModel{FieldA: "ABC", FieldB: "def", FieldC: 3})
+	assert.NoError(t, err)
+
+	got, err := obj.Read(ctx, HookArgs{ID: "id03"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "abc", got.FieldA)
+		assert.Equal(t, "DEF", got.FieldB)
+		assert.Equal(t, time.Date(2001, 9, 28, 23, 0, 0, 0, time.UTC), got.CreatedAt.UTC())
+	}
+
+	assert.NoError(t, obj.Update(ctx, HookArgs{ID: "id03"}, got))
+
+	got, err = obj.Read(ctx, HookArgs{ID: "id03"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2002, 9, 28, 23, 0, 0, 0, time.UTC), got.UpdatedAt.UTC())
+	}
+
+	assert.ErrorIs(t, obj.Delete(ctx, HookArgs{ID: "ro01"}), errReadOnly)
+	assert.NoError(t, obj.Delete(ctx, HookArgs{ID: "id03"}))
+}
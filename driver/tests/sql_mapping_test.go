@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+type ModelNested struct {
+	ID     string `db:"id"`
+	Fields struct {
+		A string
+		B string
+	}
+	Meta struct {
+		C int `json:"c"`
+	} `db:"meta"`
+}
+
+func TestView_Read_Mapping(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	type meta = struct {
+		C int `json:"c"`
+	}
+
+	view := normsql.NewView[ModelNested, FilterID](db, `
+	SELECT
+		"id",
+		"field_a" AS "fields.a",
+		"field_b" AS "fields.b",
+		json_build_object('c', "field_c") AS "meta",
+		"created_at"
+	FROM
+		"tests"
+	WHERE
+		"id" = {{ .A.ID }}
+	;`,
+		normsql.WithSnakeCase(),
+		normsql.WithLenientColumns(),
+		normsql.WithConverter(normsql.FromJSON[meta]),
+	)
+
+	got, err := view.Read(context.Background(), FilterID{ID: "id01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ModelNested{ID: "id01"}
+	want.Fields.A = "a"
+	want.Fields.B = "b"
+	want.Meta.C = 1234
+
+	assert.Equal(t, want, got)
+}
+
+func TestView_Read_Mapping_Error_Strict(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewView[ModelShort, FilterID](db, `
+	SELECT "field_a", "field_b", "field_c", "created_at" FROM "tests" WHERE "id" = {{ .A.ID }};`)
+
+	_, err := view.Read(context.Background(), FilterID{ID: "id01"})
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `"created_at"`)
+	}
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+type ModelWithTotal struct {
+	Header ModelShort
+	Lines  []ModelShort
+	Total  int64
+}
+
+// lib/pq returns several result sets only for queries without parameters.
+const multiQuery = `
+SELECT "field_a", "field_b", "field_c" FROM "tests" WHERE "id" = 'id01';
+SELECT "field_a", "field_b", "field_c" FROM "tests" ORDER BY "id";
+SELECT count(*) FROM "tests";
+`
+
+func TestMultiView_Read(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	var view norm.View[ModelWithTotal, struct{}] = normsql.NewMultiView[ModelWithTotal, struct{}](db, multiQuery)
+
+	want := ModelWithTotal{
+		Header: ModelShort{FieldA: "a", FieldB: "b", FieldC: 1234},
+		Lines: []ModelShort{
+			{FieldA: "a", FieldB: "b", FieldC: 1234},
+			{FieldA: "aaaa", FieldB: "bbbb", FieldC: 4321},
+		},
+		Total: 2,
+	}
+
+	got, err := view.Read(context.Background(), struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, want, got)
+}
+
+func TestMultiView_Read_WithTransaction(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM "tests" WHERE "id" = 'id02';`); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewMultiView[ModelWithTotal, struct{}](db, multiQuery)
+
+	got, err := view.Read(normsql.WithTransaction(context.Background(), tx), struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []ModelShort{{FieldA: "a", FieldB: "b", FieldC: 1234}}, got.Lines)
+	assert.Equal(t, int64(1), got.Total)
+}
+
+func TestMultiView_Read_Error_NotFound(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewMultiView[ModelWithTotal, struct{}](db, `
+SELECT "field_a", "field_b", "field_c" FROM "tests" WHERE "id" = '-1';
+SELECT "field_a", "field_b", "field_c" FROM "tests" ORDER BY "id";
+SELECT count(*) FROM "tests";
+`)
+
+	_, err := view.Read(context.Background(), struct{}{})
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+}
+
+func TestMultiView_Read_Error_MissingResultSet(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewMultiView[ModelWithTotal, struct{}](db, `
+SELECT "field_a", "field_b", "field_c" FROM "tests" WHERE "id" = 'id01';
+SELECT "field_a", "field_b", "field_c" FROM "tests" ORDER BY "id";
+`)
+
+	_, err := view.Read(context.Background(), struct{}{})
+	if assert.Error(t, err) {
+		assert.EqualError(t, err, "query returned 2 result sets, want 3")
+	}
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+var pageSecret = []byte("page cursor secret of the tests")
+
+func (m Model) Key() string {
+	return m.ID
+}
+
+func pageIDs(page norm.Page[Model]) (ids []string) {
+	for _, m := range page.Items {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestOffsetPager_Page(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"id03", "id04", "id05"} {
+		if err := create(t, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var pager norm.Pager[Model, struct{}] = normsql.NewOffsetPager[Model, struct{}](db, pageSecret, `
+	SELECT
+		"id",
+		"field_a",
+		"field_b",
+		"field_c",
+		"created_at",
+		"updated_at"
+	FROM
+		"tests"
+	ORDER BY
+		"id" ASC
+	LIMIT {{ .P.Limit }} OFFSET {{ .P.Offset }}
+	;`,
+	)
+
+	var got [][]string
+
+	req := norm.PageRequest{Limit: 2}
+
+	for {
+		page, err := pager.Page(context.Background(), struct{}{}, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, pageIDs(page))
+
+		if !page.HasNext {
+			assert.Empty(t, page.Next)
+			break
+		}
+
+		req.Cursor = page.Next
+	}
+
+	assert.Equal(t, [][]string{{"id01", "id02"}, {"id03", "id04"}, {"id05"}}, got)
+}
+
+func TestKeysetPager_Page(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"id03", "id04"} {
+		if err := create(t, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pager := normsql.NewKeysetPager[Model, struct{}, string](db, pageSecret, `
+	SELECT
+		"id",
+		"field_a",
+		"field_b",
+		"field_c",
+		"created_at",
+		"updated_at"
+	FROM
+		"tests"
+	WHERE
+		{{ if .P.HasAfter }} "id" > {{ .P.After }} {{ else }} TRUE {{ end }}
+	ORDER BY
+		"id" ASC
+	LIMIT {{ .P.Limit }}
+	;`,
+	)
+
+	var got [][]string
+
+	req := norm.PageRequest{Limit: 2}
+
+	for {
+		page, err := pager.Page(context.Background(), struct{}{}, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, pageIDs(page))
+
+		if !page.HasNext {
+			break
+		}
+
+		last, ok := norm.LastKey[Model, string](page)
+		if assert.True(t, ok) {
+			assert.Equal(t, page.Items[len(page.Items)-1].ID, last)
+		}
+
+		req.Cursor = page.Next
+	}
+
+	assert.Equal(t, [][]string{{"id01", "id02"}, {"id03", "id04"}}, got)
+
+	_, err := pager.Page(context.Background(), struct{}{}, norm.PageRequest{Limit: 2, Cursor: "forged.cursor"})
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrBadCursor)
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"embed"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+//go:embed testdata/*.sql
+var queriesFS embed.FS
+
+func TestObjectByName(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := normsql.LoadQueries(queriesFS, "testdata/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := normsql.ObjectByName[ModelShort, Args](db, q, "CreateTest", "ReadTest", "UpdateTest", "DeleteTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := Args{
+		ID:        "qwerty",
+		CreatedAt: time.Date(2001, 9, 28, 23, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2001, 9, 28, 23, 0, 0, 0, time.UTC),
+	}
+
+	want := ModelShort{
+		FieldA: "a",
+		FieldB: "b",
+		FieldC: 1,
+	}
+
+	if err := obj.Create(context.Background(), args, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := obj.Read(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, want, got)
+
+	if err := obj.Delete(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = obj.Read(context.Background(), args)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+}
+
+func TestViewByName_Error_Missing(t *testing.T) {
+	q, err := normsql.LoadQueries(queriesFS, "testdata/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = normsql.ViewByName[Model, FilterID](db, q, "ReadUnknown")
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `"ReadUnknown"`)
+	}
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func openReplica(t *testing.T) *sql.DB {
+	t.Helper()
+
+	replica, err := sql.Open("postgres", databaseUrl+"&application_name=replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { replica.Close() })
+
+	return replica
+}
+
+func TestRouter(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	router := normsql.NewRouter(db, []*sql.DB{openReplica(t)})
+	defer router.Close()
+
+	app := normsql.NewView[string, struct{}](router, `SELECT current_setting('application_name');`)
+	ctx := context.Background()
+
+	got, err := app.Read(ctx, struct{}{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "replica", got)
+	}
+
+	got, err = app.Read(normsql.WithPrimary(ctx), struct{}{})
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, "replica", got)
+	}
+
+	tx, err := router.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	got, err = app.Read(normsql.WithTransaction(ctx, tx), struct{}{})
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, "replica", got)
+	}
+
+	obj := normsql.NewImmutableObject[ModelShort, Args](router, `
+INSERT INTO "tests" VALUES (
+	{{ .A.ID }}, current_setting('application_name'), {{ .M.FieldB }}, {{ .M.FieldC }}, {{ .A.CreatedAt }}, {{ .A.UpdatedAt }}
+);`,
+		`SELECT "field_a", "field_b", "field_c" FROM "tests" WHERE "id" = {{ .A.ID }};`,
+	)
+
+	args := Args{ID: "id03", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+
+	if err := obj.Create(ctx, args, ModelShort{FieldB: "b", FieldC: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := obj.Read(normsql.WithPrimary(ctx), args)
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, "replica", written.FieldA)
+	}
+}
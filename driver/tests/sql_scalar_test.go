@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter_Count(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		c    norm.Counter[FilterIDs]
+		args FilterIDs
+		want int64
+	}{
+		{
+			name: "count",
+			c:    normsql.NewCounter[FilterIDs](db, `SELECT count(*) FROM "tests" WHERE "id" = ANY( {{ .A.IDs }} );`),
+			args: FilterIDs{IDs: []string{"-1", "id01", "id02"}},
+			want: 2,
+		},
+		{
+			name: "zero",
+			c:    normsql.NewCounter[FilterIDs](db, `SELECT count(*) FROM "tests" WHERE "id" = ANY( {{ .A.IDs }} );`),
+			args: FilterIDs{IDs: []string{"-1"}},
+			want: 0,
+		},
+		{
+			name: "no rows",
+			c: normsql.NewCounter[FilterIDs](db, `SELECT count(*) FROM "tests" WHERE "id" = ANY( {{ .A.IDs }} ) 
+				GROUP BY "field_a";`),
+			args: FilterIDs{IDs: []string{"-1"}},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.c.Count(context.Background(), tt.args)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExister_Exists(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		selectRow    = `SELECT "id", "field_a" FROM "tests" WHERE "id" = {{ .A.ID }};`
+		selectExists = `SELECT EXISTS( SELECT 1 FROM "tests" WHERE "id" = {{ .A.ID }} );`
+	)
+
+	tests := []struct {
+		name string
+		e    norm.Exister[FilterID]
+		args FilterID
+		want bool
+	}{
+		{"row exists", normsql.NewExister[FilterID](db, selectRow), FilterID{ID: "id01"}, true},
+		{"no rows", normsql.NewExister[FilterID](db, selectRow), FilterID{ID: "-1"}, false},
+		{"exists true", normsql.NewExister[FilterID](db, selectExists, normsql.WithExistsFlag()), FilterID{ID: "id01"}, true},
+		{"exists false", normsql.NewExister[FilterID](db, selectExists, normsql.WithExistsFlag()), FilterID{ID: "-1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.e.Exists(context.Background(), tt.args)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestView_Read_Scalar(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := normsql.NewView[[]string, struct{}](db, `SELECT "id" FROM "tests" ORDER BY "id" ASC;`)
+
+	gotIDs, err := ids.Read(context.Background(), struct{}{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"id01", "id02"}, gotIDs)
+	}
+
+	sum := normsql.NewView[int64, struct{}](db, `SELECT sum("field_c") FROM "tests";`)
+
+	gotSum, err := sum.Read(context.Background(), struct{}{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1234+4321), gotSum)
+	}
+
+	created := normsql.NewView[time.Time, FilterID](db, `SELECT "created_at" FROM "tests" WHERE "id" = {{ .A.ID }};`)
+
+	gotCreated, err := created.Read(context.Background(), FilterID{ID: "id01"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2001, 9, 28, 23, 0, 0, 0, time.UTC), gotCreated.UTC())
+	}
+
+	_, err = created.Read(context.Background(), FilterID{ID: "-1"})
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+}
+
+func TestView_Read_Scalar_Error_Columns(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := normsql.NewView[[]string, struct{}](db, `SELECT "id", "field_a" FROM "tests";`)
+
+	_, err := ids.Read(context.Background(), struct{}{})
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "exactly one column")
+	}
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestView_Scope(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewView[[]string, struct{}](db, `SELECT "id" FROM "tests" WHERE "field_a" = {{ .C.tenant }} ORDER BY "id";`,
+		normsql.WithRequiredScope("tenant"))
+
+	_, err := view.Read(context.Background(), struct{}{})
+	assert.ErrorIs(t, err, normsql.ErrMissingScope)
+
+	ids, err := view.Read(normsql.WithScope(context.Background(), "tenant", "a"), struct{}{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"id01"}, ids)
+	}
+}
+
+func TestView_LocalSetting(t *testing.T) {
+	view := normsql.NewView[string, struct{}](db, `SELECT current_setting('app.tenant', true);`,
+		normsql.WithLocalSetting("app.tenant", "tenant"))
+
+	ctx := normsql.WithScope(context.Background(), "tenant", "t1")
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	got, err := view.Read(normsql.WithTransaction(ctx, tx), struct{}{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "t1", got)
+	}
+
+	assert.NoError(t, tx.Rollback())
+
+	got, err = view.Read(ctx, struct{}{})
+	if assert.NoError(t, err) {
+		assert.Empty(t, got)
+	}
+}
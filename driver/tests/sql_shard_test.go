@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+// openShards makes shards as schemas of the test database.
+func openShards(t *testing.T, n int) []normsql.DB {
+	t.Helper()
+
+	var shards []normsql.DB
+
+	for i := 0; i < n; i++ {
+		schema := fmt.Sprintf("shard%d", i)
+
+		qq := []string{
+			`DROP SCHEMA IF EXISTS "` + schema + `" CASCADE;`,
+			`CREATE SCHEMA "` + schema + `";`,
+			`CREATE TABLE "` + schema + `"."tests" (LIKE "public"."tests");`,
+		}
+		for _, q := range qq {
+			if _, err := db.Exec(q); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		shard, err := sql.Open("postgres", databaseUrl+"&search_path="+schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { shard.Close() })
+
+		shards = append(shards, shard)
+	}
+
+	return shards
+}
+
+func TestSharded(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	shards := openShards(t, 2)
+
+	_, c, r, u, d := setupQueries()
+	obj, err := normsql.NewSharded[ModelShort, Args](shards, normsql.HashShard(2, func(args Args) string { return args.ID }), c, r, u, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var _ norm.Object[ModelShort, Args] = obj
+
+	ctx := context.Background()
+	ts := time.Date(2001, 9, 28, 23, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 10; i++ {
+		args := Args{ID: fmt.Sprintf("id%02d", i), CreatedAt: ts, UpdatedAt: ts}
+		if err := obj.Create(ctx, args, ModelShort{FieldA: "a", FieldB: "b", FieldC: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, shard := range shards {
+		var n int
+		if err := shard.(*sql.DB).QueryRow(`SELECT count(*) FROM "tests";`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		assert.NotZero(t, n, "shard %d is empty", i)
+	}
+
+	got, err := obj.Read(ctx, Args{ID: "id07"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 7, got.FieldC)
+	}
+
+	if err := obj.Delete(ctx, Args{ID: "id07"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = obj.Read(ctx, Args{ID: "id07"})
+	assert.ErrorIs(t, err, norm.ErrNotFound)
+
+	view, err := normsql.NewShardedView[Model, struct{}](shards, `SELECT * FROM "tests" ORDER BY "id";`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := view.Ordered(func(a, b Model) bool { return a.ID < b.ID }).Read(ctx, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, m := range all {
+		ids = append(ids, m.ID)
+	}
+
+	assert.Equal(t, []string{"id00", "id01", "id02", "id03", "id04", "id05", "id06", "id08", "id09"}, ids)
+}
+
+func TestShardedView_Read_Error(t *testing.T) {
+	shards := openShards(t, 2)
+
+	view, err := normsql.NewShardedView[Model, struct{}](shards, `SELECT * FROM "missing";`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = view.Read(context.Background(), struct{}{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetSoftDeleteDB(t testing.TB, db *sql.DB) error {
+	t.Helper()
+
+	qq := []string{
+		`DROP TABLE IF EXISTS "tests_soft";`,
+		`CREATE TABLE "tests_soft" (
+			"id" text PRIMARY KEY,
+			"field_a" text NOT NULL,
+			"field_b" text NOT NULL,
+			"field_c" int NOT NULL,
+			"deleted_at" TIMESTAMP WITH TIME ZONE
+		);`,
+		`INSERT INTO "tests_soft" VALUES('id01', 'a', 'b', 1234, NULL);`,
+		`INSERT INTO "tests_soft" VALUES('id02', 'aaaa', 'bbbb', 4321, timestamp '2002-09-28 23:00');`,
+	}
+
+	for _, q := range qq {
+		if _, err := db.Exec(q); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setupSoftDeleteQueries() (_ *sql.DB, c, r, u, d, rs, rd string) {
+	c = `
+INSERT INTO "tests_soft" (
+	"id",
+	"field_a",
+	"field_b",
+	"field_c"
+) VALUES (
+	{{.A.ID}},
+	{{.M.FieldA}},
+	{{.M.FieldB}},
+	{{.M.FieldC}}
+);
+`
+
+	r = `
+SELECT
+	"field_a",
+	"field_b",
+	"field_c"
+FROM
+	"tests_soft"
+WHERE
+	"id" = {{.A.ID}} AND "deleted_at" IS NULL
+;`
+
+	u = `
+UPDATE
+	"tests_soft"
+SET
+	"field_a" = {{.M.FieldA}},
+	"field_b" = {{.M.FieldB}},
+	"field_c" = {{.M.FieldC}}
+WHERE
+	"id" = {{.A.ID}} AND "deleted_at" IS NULL
+;
+`
+
+	d = `
+UPDATE
+	"tests_soft"
+SET
+	"deleted_at" = now()
+WHERE
+	"id" = {{.A.ID}} AND "deleted_at" IS NULL
+;
+`
+
+	rs = `
+UPDATE
+	"tests_soft"
+SET
+	"deleted_at" = NULL
+WHERE
+	"id" = {{.A.ID}} AND "deleted_at" IS NOT NULL
+;
+`
+
+	rd = `
+SELECT
+	"field_a",
+	"field_b",
+	"field_c"
+FROM
+	"tests_soft"
+WHERE
+	"id" = {{.A.ID}}
+;`
+
+	return db, c, r, u, d, rs, rd
+}
+
+func TestSoftDeleteObject_Delete(t *testing.T) {
+	if err := resetSoftDeleteDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	var obj norm.SoftDeleteObject[ModelShort, FilterID] = normsql.NewSoftDeleteObject[ModelShort, FilterID](setupSoftDeleteQueries())
+
+	args := FilterID{ID: "id01"}
+
+	want := ModelShort{
+		FieldA: "a",
+		FieldB: "b",
+		FieldC: 1234,
+	}
+
+	err := obj.Delete(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = obj.Read(context.Background(), args)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+
+	got, err := obj.ReadWithDeleted(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, want, got)
+
+	var count int
+	if err := db.QueryRow(`SELECT count(*) FROM "tests_soft" WHERE "id" = 'id01';`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, count)
+}
+
+func TestSoftDeleteObject_Delete_Error_NotAffected(t *testing.T) {
+	if err := resetSoftDeleteDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := normsql.NewSoftDeleteObject[ModelShort, FilterID](setupSoftDeleteQueries())
+
+	err := obj.Delete(context.Background(), FilterID{ID: "id02"})
+
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotAffected)
+	}
+}
+
+func TestSoftDeleteObject_Restore(t *testing.T) {
+	if err := resetSoftDeleteDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := normsql.NewSoftDeleteObject[ModelShort, FilterID](setupSoftDeleteQueries())
+
+	args := FilterID{ID: "id02"}
+
+	want := ModelShort{
+		FieldA: "aaaa",
+		FieldB: "bbbb",
+		FieldC: 4321,
+	}
+
+	_, err := obj.Read(context.Background(), args)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotFound)
+	}
+
+	err = obj.Restore(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := obj.Read(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, want, got)
+
+	err = obj.Restore(context.Background(), args)
+	if assert.Error(t, err) {
+		assert.ErrorIs(t, err, norm.ErrNotAffected)
+	}
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+type Filter struct {
+	IDs    *[]string
+	FieldA *string
+	Sort   string
+}
+
+func TestView_Read_TemplateFuncs(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	view := normsql.NewView[[]string, Filter](db, `
+	SELECT
+		"id"
+	FROM
+		"tests"
+	{{ where (all
+		(cond "\"id\" IN ?" (in .A.IDs))
+		(cond "\"field_a\" = ?" .A.FieldA)
+	) }}
+	{{ orderBy .A.Sort "id" "field_a" }}
+	;`,
+	)
+
+	fieldA := "aaaa"
+
+	tests := []struct {
+		name string
+		args Filter
+		want []string
+	}{
+		{"all", Filter{IDs: &[]string{"id01", "id02"}, Sort: "id"}, []string{"id01", "id02"}},
+		{"desc", Filter{IDs: &[]string{"id01", "id02"}, Sort: "-id"}, []string{"id02", "id01"}},
+		{"optional filter", Filter{IDs: &[]string{"id01", "id02"}, FieldA: &fieldA}, []string{"id02"}},
+		{"no filters", Filter{Sort: "id"}, []string{"id01", "id02"}},
+		{"empty list", Filter{IDs: &[]string{}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := view.Read(context.Background(), tt.args)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
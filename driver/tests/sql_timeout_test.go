@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/WinPooh32/norm"
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestView_Timeout(t *testing.T) {
+	view := normsql.NewView[int, struct{}](db, `SELECT 1 FROM pg_sleep(1);`,
+		normsql.WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+
+	_, err := view.Read(context.Background(), struct{}{})
+	assert.ErrorIs(t, err, norm.ErrTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestView_StatementTimeout(t *testing.T) {
+	view := normsql.NewView[string, struct{}](db, `SELECT current_setting('statement_timeout');`,
+		normsql.WithTimeout(2*time.Second, norm.OpRead), normsql.WithStatementTimeout())
+
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	got, err := view.Read(normsql.WithTransaction(ctx, tx), struct{}{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2s", got)
+	}
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObject_ValidateDB(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := normsql.NewObject[ModelShort, Args](setupQueries())
+
+	assert.NoError(t, obj.Validate())
+	assert.NoError(t, obj.ValidateDB(context.Background()))
+}
+
+func TestView_ValidateDB_Error(t *testing.T) {
+	if err := resetDB(t, db); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		tpl     string
+		wantErr string
+	}{
+		{"unknown field", `SELECT "id" FROM "tests" WHERE "id" = {{ .A.Name }};`, "unknown field .A.Name"},
+		{"syntax", `SELEC "id" FROM "tests" WHERE "id" = {{ .A.ID }};`, "prepare read query"},
+		{"unknown table", `SELECT "id" FROM "unknown" WHERE "id" = {{ .A.ID }};`, "prepare read query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := normsql.NewView[Model, FilterID](db, tt.tpl)
+
+			err := view.ValidateDB(context.Background())
+
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
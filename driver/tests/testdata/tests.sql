@@ -0,0 +1,47 @@
+-- name: CreateTest
+INSERT INTO "tests" (
+	"id",
+	"field_a",
+	"field_b",
+	"field_c",
+	"created_at",
+	"updated_at"
+) VALUES (
+	{{.A.ID}},
+	{{.M.FieldA}},
+	{{.M.FieldB}},
+	{{.M.FieldC}},
+	{{.A.CreatedAt}},
+	{{.A.UpdatedAt}}
+);
+
+-- name: ReadTest
+SELECT
+	"field_a",
+	"field_b",
+	"field_c"
+FROM
+	"tests"
+WHERE
+	"id" = {{.A.ID}}
+;
+
+-- name: UpdateTest
+UPDATE
+	"tests"
+SET
+	"field_a" = {{.M.FieldA}},
+	"field_b" = {{.M.FieldB}},
+	"field_c" = {{.M.FieldC}},
+	"updated_at" = {{.A.UpdatedAt}}
+WHERE
+	"id" = {{.A.ID}}
+;
+
+-- name: DeleteTest
+DELETE
+FROM
+	"tests"
+WHERE
+	"id" = {{.A.ID}}
+;
@@ -0,0 +1,99 @@
+package norm
+
+import "context"
+
+// Guard runs operations of guarded objects, e.g. rejecting or delaying them.
+// It must call fn at most once and return its error.
+type Guard[A any] interface {
+	Guard(ctx context.Context, op Op, args A, fn func(ctx context.Context) error) error
+}
+
+// GuardFunc is an adapter to use function as Guard.
+type GuardFunc[A any] func(ctx context.Context, op Op, args A, fn func(ctx context.Context) error) error
+
+func (f GuardFunc[A]) Guard(ctx context.Context, op Op, args A, fn func(ctx context.Context) error) error {
+	return f(ctx, op, args, fn)
+}
+
+// GuardReader returns reader which reads through the guard.
+func GuardReader[M, A any](r Reader[M, A], g Guard[A]) Reader[M, A] {
+	return guardedReader[M, A]{r, g}
+}
+
+// GuardCreator returns creator which creates through the guard.
+func GuardCreator[M, A any](c Creator[M, A], g Guard[A]) Creator[M, A] {
+	return guardedCreator[M, A]{c, g}
+}
+
+// GuardUpdater returns updater which updates through the guard.
+func GuardUpdater[M, A any](u Updater[M, A], g Guard[A]) Updater[M, A] {
+	return guardedUpdater[M, A]{u, g}
+}
+
+// GuardDeleter returns deleter which deletes through the guard.
+func GuardDeleter[M, A any](d Deleter[M, A], g Guard[A]) Deleter[M, A] {
+	return guardedDeleter[M, A]{d, g}
+}
+
+// GuardObject returns object which runs all operations through the guard.
+func GuardObject[M, A any](o Object[M, A], g Guard[A]) Object[M, A] {
+	return guardedObject[M, A]{
+		guardedCreator[M, A]{o, g},
+		guardedReader[M, A]{o, g},
+		guardedUpdater[M, A]{o, g},
+		guardedDeleter[M, A]{o, g},
+	}
+}
+
+type guardedObject[M, A any] struct {
+	guardedCreator[M, A]
+	guardedReader[M, A]
+	guardedUpdater[M, A]
+	guardedDeleter[M, A]
+}
+
+type guardedReader[M, A any] struct {
+	r Reader[M, A]
+	g Guard[A]
+}
+
+func (w guardedReader[M, A]) Read(ctx context.Context, args A) (value M, err error) {
+	err = w.g.Guard(ctx, OpRead, args, func(ctx context.Context) error {
+		value, err = w.r.Read(ctx, args)
+		return err
+	})
+	return value, err
+}
+
+type guardedCreator[M, A any] struct {
+	c Creator[M, A]
+	g Guard[A]
+}
+
+func (w guardedCreator[M, A]) Create(ctx context.Context, args A, value M) error {
+	return w.g.Guard(ctx, OpCreate, args, func(ctx context.Context) error {
+		return w.c.Create(ctx, args, value)
+	})
+}
+
+type guardedUpdater[M, A any] struct {
+	u Updater[M, A]
+	g Guard[A]
+}
+
+func (w guardedUpdater[M, A]) Update(ctx context.Context, args A, value M) error {
+	return w.g.Guard(ctx, OpUpdate, args, func(ctx context.Context) error {
+		return w.u.Update(ctx, args, value)
+	})
+}
+
+type guardedDeleter[M, A any] struct {
+	d Deleter[M, A]
+	g Guard[A]
+}
+
+func (w guardedDeleter[M, A]) Delete(ctx context.Context, args A) error {
+	return w.g.Guard(ctx, OpDelete, args, func(ctx context.Context) error {
+		return w.d.Delete(ctx, args)
+	})
+}
@@ -0,0 +1,99 @@
+package norm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// BeforeCreateHook is called by drivers on the model and args before create.
+// Hooks of values are called with pointer receiver, so they can modify values, e.g. set timestamps.
+type BeforeCreateHook interface {
+	BeforeCreate(ctx context.Context) error
+}
+
+// AfterReadHook is called by drivers on the read model, on every element of a slice model.
+type AfterReadHook interface {
+	AfterRead(ctx context.Context) error
+}
+
+// BeforeUpdateHook is called by drivers on the model and args before update.
+type BeforeUpdateHook interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// BeforeDeleteHook is called by drivers on args before delete.
+type BeforeDeleteHook interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// CallBeforeCreate calls BeforeCreate hooks of args and value, an error aborts the create.
+func CallBeforeCreate[M, A any](ctx context.Context, args *A, value *M) error {
+	for _, p := range []any{args, value} {
+		if h, ok := hookOf[BeforeCreateHook](reflect.ValueOf(p)); ok {
+			if err := h.BeforeCreate(ctx); err != nil {
+				return fmt.Errorf("before create hook: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// CallBeforeUpdate calls BeforeUpdate hooks of args and value, an error aborts the update.
+func CallBeforeUpdate[M, A any](ctx context.Context, args *A, value *M) error {
+	for _, p := range []any{args, value} {
+		if h, ok := hookOf[BeforeUpdateHook](reflect.ValueOf(p)); ok {
+			if err := h.BeforeUpdate(ctx); err != nil {
+				return fmt.Errorf("before update hook: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// CallBeforeDelete calls BeforeDelete hook of args, an error aborts the delete.
+func CallBeforeDelete[A any](ctx context.Context, args *A) error {
+	if h, ok := hookOf[BeforeDeleteHook](reflect.ValueOf(args)); ok {
+		if err := h.BeforeDelete(ctx); err != nil {
+			return fmt.Errorf("before delete hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// CallAfterRead calls AfterRead hook of value or of every element when value is a slice without the hook.
+func CallAfterRead[M any](ctx context.Context, value *M) error {
+	p := reflect.ValueOf(value)
+
+	if h, ok := hookOf[AfterReadHook](p); ok {
+		if err := h.AfterRead(ctx); err != nil {
+			return fmt.Errorf("after read hook: %w", err)
+		}
+		return nil
+	}
+
+	if v := p.Elem(); v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			h, ok := hookOf[AfterReadHook](v.Index(i).Addr())
+			if !ok {
+				return nil
+			}
+			if err := h.AfterRead(ctx); err != nil {
+				return fmt.Errorf("after read hook of element %d: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hookOf returns hook implemented by the pointer or the pointed value.
+func hookOf[H any](p reflect.Value) (h H, ok bool) {
+	if h, ok = p.Interface().(H); ok {
+		return h, true
+	}
+	if e := p.Elem(); e.Kind() != reflect.Pointer || !e.IsNil() {
+		h, ok = e.Interface().(H)
+	}
+	return h, ok
+}
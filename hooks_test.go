@@ -0,0 +1,109 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type hookArgs struct {
+	ID string
+}
+
+func (a *hookArgs) BeforeCreate(ctx context.Context) error {
+	a.ID = strings.ToLower(a.ID)
+	return nil
+}
+
+func (a *hookArgs) BeforeDelete(ctx context.Context) error {
+	if a.ID == "" {
+		return errors.New("empty id")
+	}
+	return nil
+}
+
+type hookModel struct {
+	Name  string
+	Calls int
+}
+
+func (m *hookModel) BeforeCreate(ctx context.Context) error {
+	m.Calls++
+	return nil
+}
+
+func (m *hookModel) AfterRead(ctx context.Context) error {
+	m.Name = strings.TrimSpace(m.Name)
+	return nil
+}
+
+func TestCallBeforeCreate(t *testing.T) {
+	args := hookArgs{ID: "ID01"}
+	value := hookModel{}
+
+	if err := CallBeforeCreate(context.Background(), &args, &value); err != nil {
+		t.Fatal(err)
+	}
+	if args.ID != "id01" {
+		t.Errorf("args.ID = %q, want %q", args.ID, "id01")
+	}
+	if value.Calls != 1 {
+		t.Errorf("value.Calls = %d, want 1", value.Calls)
+	}
+}
+
+func TestCallBeforeCreate_Pointer(t *testing.T) {
+	args := hookArgs{}
+	value := &hookModel{}
+
+	if err := CallBeforeCreate(context.Background(), &args, &value); err != nil {
+		t.Fatal(err)
+	}
+	if value.Calls != 1 {
+		t.Errorf("value.Calls = %d, want 1", value.Calls)
+	}
+}
+
+func TestCallBeforeUpdate_NoHooks(t *testing.T) {
+	args, value := 1, "a"
+	if err := CallBeforeUpdate(context.Background(), &args, &value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallBeforeDelete_Error(t *testing.T) {
+	err := CallBeforeDelete(context.Background(), &hookArgs{})
+	if err == nil || !strings.Contains(err.Error(), "empty id") {
+		t.Errorf("CallBeforeDelete() error = %v, want empty id", err)
+	}
+}
+
+func TestCallAfterRead(t *testing.T) {
+	tests := []struct {
+		name  string
+		value []hookModel
+		want  []string
+	}{
+		{name: "nil", value: nil, want: nil},
+		{name: "slice", value: []hookModel{{Name: " a "}, {Name: "b "}}, want: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CallAfterRead(context.Background(), &tt.value); err != nil {
+				t.Fatal(err)
+			}
+			for i, v := range tt.value {
+				if v.Name != tt.want[i] {
+					t.Errorf("value[%d].Name = %q, want %q", i, v.Name, tt.want[i])
+				}
+			}
+		})
+	}
+
+	var nilPtr *hookModel
+	if err := CallAfterRead(context.Background(), &nilPtr); err != nil {
+		t.Fatal(err)
+	}
+}
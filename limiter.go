@@ -0,0 +1,238 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError reports operation rejected by Limiter, it matches ErrRateLimited.
+type RateLimitError struct {
+	Op  Op
+	Key string
+	// RetryAfter is the time until a token is available.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s of %q: rate limit exceeded, retry after %s", e.Op, e.Key, e.RetryAfter)
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Rate is a token bucket of Burst tokens refilled at Limit tokens per second.
+// The zero Rate is unlimited.
+type Rate struct {
+	Limit float64
+	Burst int
+}
+
+// Every returns rate of a token per interval with the burst.
+func Every(interval time.Duration, burst int) Rate {
+	return Rate{Limit: float64(time.Second) / float64(interval), Burst: burst}
+}
+
+func (r Rate) unlimited() bool {
+	return r.Limit <= 0 || r.Burst <= 0
+}
+
+// refillTime returns time of filling the empty bucket.
+func (r Rate) refillTime() time.Duration {
+	if r.unlimited() {
+		return 0
+	}
+	return time.Duration(float64(r.Burst) / r.Limit * float64(time.Second))
+}
+
+// LimiterOption configures Limiter.
+type LimiterOption func(*limiterOptions)
+
+type limiterOptions struct {
+	ops  map[Op]Rate
+	wait bool
+	now  func() time.Time
+}
+
+// WithOpLimit sets own rate of the operation, its tokens are counted apart from other operations.
+func WithOpLimit(op Op, r Rate) LimiterOption {
+	return func(o *limiterOptions) {
+		if o.ops == nil {
+			o.ops = map[Op]Rate{}
+		}
+		o.ops[op] = r
+	}
+}
+
+// WithLimitWait makes operations wait for a token until the context is done instead of rejecting them.
+// Operations which can't get a token before the context deadline are rejected immediately.
+func WithLimitWait() LimiterOption {
+	return func(o *limiterOptions) {
+		o.wait = true
+	}
+}
+
+// WithLimiterClock sets time source of the limiter, time.Now by default.
+func WithLimiterClock(now func() time.Time) LimiterOption {
+	return func(o *limiterOptions) {
+		o.now = now
+	}
+}
+
+// Limiter is a Guard limiting rate of operations per key, e.g. per tenant:
+//
+//	reports = norm.GuardReader[Report, ReportArgs](reports, norm.NewLimiter(norm.Every(time.Second, 5),
+//		func(ctx context.Context, args ReportArgs) string { return args.TenantID },
+//	))
+//
+// Operations over the limit fail with *RateLimitError unless WithLimitWait is set.
+type Limiter[A any] struct {
+	rate       Rate
+	key        func(ctx context.Context, args A) string
+	opts       limiterOptions
+	pruneEvery time.Duration
+	mu         sync.Mutex
+	buckets    map[bucketKey]*bucket
+	lastPrune  time.Time
+}
+
+type bucketKey struct {
+	op  Op
+	key string
+}
+
+type bucket struct {
+	rate   Rate
+	tokens float64
+	last   time.Time
+}
+
+// maxIdleBuckets is the number of buckets after which full buckets are dropped.
+// Buckets are pruned at most once per refill time of the slowest rate, since idle buckets get full by then.
+const maxIdleBuckets = 1024
+
+// NewLimiter makes limiter of the rate, key returns key of the operation args. A nil key limits all operations together.
+func NewLimiter[A any](r Rate, key func(ctx context.Context, args A) string, opts ...LimiterOption) *Limiter[A] {
+	o := limiterOptions{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	l := &Limiter[A]{rate: r, key: key, opts: o, buckets: map[bucketKey]*bucket{}}
+
+	l.pruneEvery = r.refillTime()
+	for _, or := range o.ops {
+		if d := or.refillTime(); d > l.pruneEvery {
+			l.pruneEvery = d
+		}
+	}
+
+	return l
+}
+
+func (l *Limiter[A]) Guard(ctx context.Context, op Op, args A, fn func(ctx context.Context) error) error {
+	r, bk := l.rate, bucketKey{}
+
+	if or, ok := l.opts.ops[op]; ok {
+		r, bk.op = or, op
+	}
+
+	if r.unlimited() {
+		return fn(ctx)
+	}
+
+	if l.key != nil {
+		bk.key = l.key(ctx, args)
+	}
+
+	delay, ok := l.take(ctx, bk, r)
+	if !ok {
+		return &RateLimitError{Op: op, Key: bk.key, RetryAfter: delay}
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.putBack(bk)
+			return ctx.Err()
+		}
+	}
+
+	return fn(ctx)
+}
+
+// take takes a token of the bucket and returns delay until it is available.
+// It fails when the token must not be waited for.
+func (l *Limiter[A]) take(ctx context.Context, bk bucketKey, r Rate) (delay time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.opts.now()
+
+	if len(l.buckets) >= maxIdleBuckets && now.Sub(l.lastPrune) >= l.pruneEvery {
+		l.prune(now)
+		l.lastPrune = now
+	}
+
+	b, ok := l.buckets[bk]
+	if !ok {
+		b = &bucket{rate: r, tokens: float64(r.Burst), last: now}
+		l.buckets[bk] = b
+	}
+
+	b.advance(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+
+	delay = time.Duration(math.Ceil((1 - b.tokens) / r.Limit * float64(time.Second)))
+
+	if !l.opts.wait {
+		return delay, false
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(now) < delay {
+		return delay, false
+	}
+
+	b.tokens--
+
+	return delay, true
+}
+
+// putBack returns token of the canceled wait.
+func (l *Limiter[A]) putBack(bk bucketKey) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[bk]; ok {
+		b.tokens = math.Min(b.tokens+1, float64(b.rate.Burst))
+	}
+}
+
+// prune drops full buckets, they are the same as new ones.
+func (l *Limiter[A]) prune(now time.Time) {
+	for k, b := range l.buckets {
+		if b.advance(now); b.tokens >= float64(b.rate.Burst) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+func (b *bucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(b.rate.Burst), b.tokens+elapsed.Seconds()*b.rate.Limit)
+		b.last = now
+	}
+}
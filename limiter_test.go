@@ -0,0 +1,160 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type limitArgs struct {
+	Tenant string
+}
+
+func tenantKey(ctx context.Context, args limitArgs) string {
+	return args.Tenant
+}
+
+func TestLimiter_Reject(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewLimiter(Every(time.Second, 2), tenantKey, WithLimiterClock(clock.Now))
+
+	run := func(tenant string) error {
+		return l.Guard(context.Background(), OpRead, limitArgs{Tenant: tenant}, func(ctx context.Context) error { return nil })
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := run("a"); err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+	}
+
+	err := run("a")
+
+	var lerr *RateLimitError
+	if !errors.As(err, &lerr) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("error = %v, want *RateLimitError", err)
+	}
+	if lerr.Key != "a" || lerr.Op != OpRead || lerr.RetryAfter != time.Second {
+		t.Errorf("error = %+v", lerr)
+	}
+
+	// other tenants have own buckets
+	if err := run("b"); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+
+	if err := run("a"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("error = %v, want %v", err, ErrRateLimited)
+	}
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+
+	if err := run("a"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiter_Prune(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewLimiter(Every(time.Second, 1), tenantKey, WithLimiterClock(clock.Now))
+
+	run := func(tenant string) {
+		_ = l.Guard(context.Background(), OpRead, limitArgs{Tenant: tenant}, func(ctx context.Context) error { return nil })
+	}
+
+	for i := 0; i < 2*maxIdleBuckets; i++ {
+		run(strconv.Itoa(i))
+	}
+
+	// the first prune has found no full buckets, the next one waits for the refill
+	if n := len(l.buckets); n != 2*maxIdleBuckets {
+		t.Fatalf("buckets = %d, want %d", n, 2*maxIdleBuckets)
+	}
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	run("a")
+
+	if n := len(l.buckets); n != 2*maxIdleBuckets+1 {
+		t.Fatalf("buckets before refill = %d, want %d", n, 2*maxIdleBuckets+1)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	run("b")
+
+	if n := len(l.buckets); n != 1 {
+		t.Fatalf("buckets after refill = %d, want 1", n)
+	}
+}
+
+func TestLimiter_OpLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewLimiter(Rate{}, tenantKey, WithOpLimit(OpRead, Every(time.Second, 1)), WithLimiterClock(clock.Now))
+
+	obj := GuardObject[auditModel, limitArgs](&limitObject{}, l)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := obj.Create(ctx, limitArgs{}, auditModel{}); err != nil {
+			t.Fatalf("unlimited create: %v", err)
+		}
+	}
+
+	if _, err := obj.Read(ctx, limitArgs{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := obj.Read(ctx, limitArgs{}); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Read() error = %v, want %v", err, ErrRateLimited)
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l := NewLimiter[limitArgs](Every(20*time.Millisecond, 1), nil, WithLimitWait())
+
+	run := func(ctx context.Context) error {
+		return l.Guard(ctx, OpRead, limitArgs{}, func(ctx context.Context) error { return nil })
+	}
+
+	if err := run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+
+	if err := run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := time.Since(start); d < 10*time.Millisecond {
+		t.Errorf("waited %s, want about 20ms", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	if err := run(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("error = %v, want %v", err, ErrRateLimited)
+	}
+}
+
+type limitObject struct{}
+
+func (limitObject) Create(ctx context.Context, args limitArgs, value auditModel) error {
+	return nil
+}
+
+func (limitObject) Read(ctx context.Context, args limitArgs) (auditModel, error) {
+	return auditModel{}, nil
+}
+
+func (limitObject) Update(ctx context.Context, args limitArgs, value auditModel) error {
+	return nil
+}
+
+func (limitObject) Delete(ctx context.Context, args limitArgs) error {
+	return nil
+}
@@ -8,6 +8,11 @@ import (
 var (
 	ErrNotFound    = errors.New("not found")
 	ErrNotAffected = errors.New("not affected by create/update")
+	ErrBadCursor   = errors.New("bad page cursor")
+	ErrTimeout     = errors.New("timeout")
+
+	// ErrMissingScope is returned when the context lacks a scope value required by the object.
+	ErrMissingScope = errors.New("missing scope value")
 )
 
 type Creator[M, A any] interface {
@@ -26,6 +31,22 @@ type Deleter[M, A any] interface {
 	Delete(ctx context.Context, args A) error
 }
 
+type Counter[A any] interface {
+	Count(ctx context.Context, args A) (n int64, err error)
+}
+
+type Exister[A any] interface {
+	Exists(ctx context.Context, args A) (ok bool, err error)
+}
+
+type Restorer[M, A any] interface {
+	Restore(ctx context.Context, args A) error
+}
+
+type DeletedReader[M, A any] interface {
+	ReadWithDeleted(ctx context.Context, args A) (value M, err error)
+}
+
 type Object[M, A any] interface {
 	Creator[M, A]
 	Reader[M, A]
@@ -33,6 +54,12 @@ type Object[M, A any] interface {
 	Deleter[M, A]
 }
 
+type SoftDeleteObject[M, A any] interface {
+	Object[M, A]
+	Restorer[M, A]
+	DeletedReader[M, A]
+}
+
 type PersistentObject[M, A any] interface {
 	Creator[M, A]
 	Reader[M, A]
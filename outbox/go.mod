@@ -0,0 +1,11 @@
+module github.com/WinPooh32/norm/outbox
+
+go 1.19
+
+require (
+	github.com/VauntDev/tqla v0.0.1
+	github.com/WinPooh32/norm/driver/sql v0.0.0-20261018231856-3db48776d691
+	github.com/mattn/go-sqlite3 v1.14.17
+)
+
+require github.com/WinPooh32/norm v0.1.2-0.20261018231856-3db48776d691 // indirect
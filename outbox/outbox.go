@@ -0,0 +1,147 @@
+// Package outbox implements transactional outbox: events are stored in a table
+// within the same transaction as the writes, then Relay hands them to a publisher.
+//
+// Enqueue events in the transaction of the driver/sql objects:
+//
+//	ctx = normsql.WithTransaction(ctx, tx)
+//
+//	if err := users.Create(ctx, args, user); err != nil {
+//		return err
+//	}
+//
+//	if err := box.Enqueue(ctx, outbox.Event{Topic: "user.created", Key: args.ID, Payload: payload}); err != nil {
+//		return err
+//	}
+//
+//	return tx.Commit()
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VauntDev/tqla"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+)
+
+var ErrNoTransaction = errors.New("outbox requires transaction")
+
+// Event is a message stored in the outbox.
+type Event struct {
+	ID        int64
+	Topic     string
+	Key       string
+	Payload   []byte
+	CreatedAt time.Time
+	// Attempts is the number of failed publications.
+	Attempts int
+}
+
+// Dialect is SQL dialect of the outbox table.
+type Dialect struct {
+	placeholder tqla.Placeholder
+	lock        string
+	schema      string
+}
+
+var (
+	// Postgres locks polled events by FOR UPDATE SKIP LOCKED, so several relays can run concurrently.
+	Postgres = Dialect{
+		placeholder: tqla.Dollar,
+		lock:        " FOR UPDATE SKIP LOCKED",
+		schema: `CREATE TABLE IF NOT EXISTS %s (
+	"id" BIGSERIAL PRIMARY KEY,
+	"topic" TEXT NOT NULL,
+	"key" TEXT NOT NULL,
+	"payload" BYTEA NOT NULL,
+	"created_at" BIGINT NOT NULL,
+	"available_at" BIGINT NOT NULL,
+	"attempts" INT NOT NULL DEFAULT 0,
+	"last_error" TEXT NOT NULL DEFAULT ''
+);`,
+	}
+
+	// SQLite relies on the database lock, run a single relay.
+	SQLite = Dialect{
+		placeholder: tqla.Question,
+		schema: `CREATE TABLE IF NOT EXISTS %s (
+	"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+	"topic" TEXT NOT NULL,
+	"key" TEXT NOT NULL,
+	"payload" BLOB NOT NULL,
+	"created_at" INTEGER NOT NULL,
+	"available_at" INTEGER NOT NULL,
+	"attempts" INTEGER NOT NULL DEFAULT 0,
+	"last_error" TEXT NOT NULL DEFAULT ''
+);`,
+	}
+)
+
+// Outbox stores events into the table.
+type Outbox struct {
+	table   string
+	dialect Dialect
+}
+
+func New(table string, d Dialect) Outbox {
+	return Outbox{table: table, dialect: d}
+}
+
+// Schema returns statement creating the outbox table.
+func (o Outbox) Schema() string {
+	return fmt.Sprintf(o.dialect.schema, o.quotedTable())
+}
+
+// Enqueue stores events in the transaction of the context set by normsql.WithTransaction.
+// It fails with ErrNoTransaction when there is no transaction, since events would not be atomic with writes.
+func (o Outbox) Enqueue(ctx context.Context, events ...Event) error {
+	tx, ok := normsql.TxFromContext(ctx)
+	if !ok {
+		return ErrNoTransaction
+	}
+
+	stmt, err := o.query(`INSERT INTO %s ("topic", "key", "payload", "created_at", "available_at") VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, e := range events {
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = now
+		}
+
+		payload := e.Payload
+		if payload == nil {
+			payload = []byte{}
+		}
+
+		if _, err := tx.ExecContext(ctx, stmt, e.Topic, e.Key, payload, e.CreatedAt.UnixMilli(), now.UnixMilli()); err != nil {
+			return fmt.Errorf("insert event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// query formats query of the outbox table, %s is replaced by the table name and "?" by placeholders.
+func (o Outbox) query(q string) (string, error) {
+	stmt, err := o.dialect.placeholder.Format(fmt.Sprintf(q, o.quotedTable()))
+	if err != nil {
+		return "", fmt.Errorf("format query: %w", err)
+	}
+	return stmt, nil
+}
+
+func (o Outbox) quotedTable() string {
+	parts := strings.Split(o.table, ".")
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
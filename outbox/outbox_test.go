@@ -0,0 +1,273 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	normsql "github.com/WinPooh32/norm/driver/sql"
+)
+
+func setupOutbox(t *testing.T) (*sql.DB, Outbox) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	o := New("outbox", SQLite)
+
+	if _, err := db.Exec(o.Schema()); err != nil {
+		t.Fatal(err)
+	}
+
+	return db, o
+}
+
+func enqueue(t *testing.T, db *sql.DB, o Outbox, events ...Event) {
+	t.Helper()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if err := o.Enqueue(normsql.WithTransaction(context.Background(), tx), events...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func countEvents(t *testing.T, db *sql.DB) (n int) {
+	t.Helper()
+
+	if err := db.QueryRow(`SELECT count(*) FROM "outbox"`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
+
+func TestOutbox_Enqueue_Error_NoTransaction(t *testing.T) {
+	_, o := setupOutbox(t)
+
+	err := o.Enqueue(context.Background(), Event{Topic: "t"})
+	if !errors.Is(err, ErrNoTransaction) {
+		t.Errorf("Enqueue() error = %v, want %v", err, ErrNoTransaction)
+	}
+}
+
+func TestOutbox_Enqueue_Rollback(t *testing.T) {
+	db, o := setupOutbox(t)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.Enqueue(normsql.WithTransaction(context.Background(), tx), Event{Topic: "t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countEvents(t, db); n != 0 {
+		t.Errorf("events after rollback = %d, want 0", n)
+	}
+}
+
+func TestRelay_Poll(t *testing.T) {
+	db, o := setupOutbox(t)
+
+	enqueue(t, db, o,
+		Event{Topic: "user.created", Key: "1", Payload: []byte(`{"id":1}`)},
+		Event{Topic: "user.created", Key: "2", Payload: []byte(`{"id":2}`)},
+		Event{Topic: "user.deleted", Key: "1"},
+	)
+
+	var got []string
+
+	relay := NewRelay(db, o, PublisherFunc(func(ctx context.Context, e Event) error {
+		got = append(got, e.Topic+":"+e.Key+":"+string(e.Payload))
+		return nil
+	}), WithBatchSize(2))
+
+	n, err := relay.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Poll() = %d, want 2", n)
+	}
+
+	n, err = relay.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("Poll() = %d, want 1", n)
+	}
+
+	want := []string{`user.created:1:{"id":1}`, `user.created:2:{"id":2}`, "user.deleted:1:"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("published = %v, want %v", got, want)
+	}
+
+	if n := countEvents(t, db); n != 0 {
+		t.Errorf("events after publication = %d, want 0", n)
+	}
+}
+
+func TestRelay_Poll_Retry(t *testing.T) {
+	db, o := setupOutbox(t)
+
+	enqueue(t, db, o, Event{Topic: "a"}, Event{Topic: "b"})
+
+	var attempts []int
+
+	relay := NewRelay(db, o, PublisherFunc(func(ctx context.Context, e Event) error {
+		if e.Topic == "b" {
+			attempts = append(attempts, e.Attempts)
+			return errors.New("broker is down")
+		}
+		return nil
+	}), WithBackoff(func(int) time.Duration { return 0 }), WithMaxAttempts(3))
+
+	for i := 0; i < 5; i++ {
+		if _, err := relay.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("attempts = %v, want %v", attempts, want)
+	}
+
+	var lastError string
+	if err := db.QueryRow(`SELECT "last_error" FROM "outbox" WHERE "topic" = 'b'`).Scan(&lastError); err != nil {
+		t.Fatal(err)
+	}
+
+	if lastError != "broker is down" {
+		t.Errorf("last error = %q", lastError)
+	}
+}
+
+func TestRelay_Poll_KeyOrder(t *testing.T) {
+	db, o := setupOutbox(t)
+
+	enqueue(t, db, o,
+		Event{Topic: "user.created", Key: "1"},
+		Event{Topic: "user.created", Key: "2"},
+		Event{Topic: "user.updated", Key: "1"},
+		Event{Topic: "user.updated", Key: "2"},
+	)
+
+	var (
+		got  []string
+		down = true
+	)
+
+	relay := NewRelay(db, o, PublisherFunc(func(ctx context.Context, e Event) error {
+		if e.Key == "1" && down {
+			return errors.New("broker is down")
+		}
+		got = append(got, e.Topic+":"+e.Key)
+		return nil
+	}), WithBackoff(func(int) time.Duration { return 0 }))
+
+	for i := 0; i < 2; i++ {
+		if _, err := relay.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want := []string{"user.created:2", "user.updated:2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("published while failing = %v, want %v", got, want)
+	}
+
+	down = false
+
+	if _, err := relay.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := relay.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"user.created:2", "user.updated:2", "user.created:1", "user.updated:1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("published = %v, want %v", got, want)
+	}
+}
+
+func TestRelay_Poll_Backoff(t *testing.T) {
+	db, o := setupOutbox(t)
+
+	enqueue(t, db, o, Event{Topic: "a"})
+
+	calls := 0
+
+	relay := NewRelay(db, o, PublisherFunc(func(ctx context.Context, e Event) error {
+		calls++
+		return errors.New("fail")
+	}), WithBackoff(func(int) time.Duration { return time.Hour }))
+
+	for i := 0; i < 3; i++ {
+		if _, err := relay.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("publications = %d, want 1", calls)
+	}
+}
+
+func TestRelay_Run(t *testing.T) {
+	db, o := setupOutbox(t)
+
+	published := make(chan Event, 1)
+
+	relay := NewRelay(db, o, PublisherFunc(func(ctx context.Context, e Event) error {
+		published <- e
+		return nil
+	}), WithInterval(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- relay.Run(ctx) }()
+
+	enqueue(t, db, o, Event{Topic: "a", Key: "k"})
+
+	select {
+	case e := <-published:
+		if e.Topic != "a" || e.Key != "k" {
+			t.Errorf("published = %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event is not published")
+	}
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v", err)
+	}
+}
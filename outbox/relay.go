@@ -0,0 +1,214 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Publisher delivers events to a message broker.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// PublisherFunc is an adapter to use function as Publisher.
+type PublisherFunc func(ctx context.Context, e Event) error
+
+func (f PublisherFunc) Publish(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// Option configures Relay.
+type Option func(*Relay)
+
+// WithBatchSize sets maximum number of events taken by one poll, 100 by default.
+func WithBatchSize(n int) Option {
+	return func(r *Relay) {
+		r.batch = n
+	}
+}
+
+// WithInterval sets delay between polls of the empty outbox, 1 second by default.
+func WithInterval(d time.Duration) Option {
+	return func(r *Relay) {
+		r.interval = d
+	}
+}
+
+// WithMaxAttempts makes relay leave events failed n times in the table, by default events are retried forever.
+// Left events hold back the later events of their keys until they are removed.
+func WithMaxAttempts(n int) Option {
+	return func(r *Relay) {
+		r.maxAttempts = n
+	}
+}
+
+// WithBackoff sets delay before the next publication of the event failed attempts times.
+func WithBackoff(backoff func(attempts int) time.Duration) Option {
+	return func(r *Relay) {
+		r.backoff = backoff
+	}
+}
+
+// WithErrorHandler sets handler of poll errors of Run, they are ignored by default.
+func WithErrorHandler(fn func(err error)) Option {
+	return func(r *Relay) {
+		r.onError = fn
+	}
+}
+
+// Relay polls the outbox and hands events of the same key to the publisher in order of enqueueing.
+// An event is not published while an earlier event of its key stays in the outbox, so a failed event
+// holds back the rest events of its key until it's published or removed. Events with empty key are unordered.
+// Published events are deleted in the same transaction, so delivery is at least once:
+// an event is published again when the transaction fails to commit.
+type Relay struct {
+	db          *sql.DB
+	outbox      Outbox
+	pub         Publisher
+	batch       int
+	interval    time.Duration
+	maxAttempts int
+	backoff     func(attempts int) time.Duration
+	onError     func(err error)
+}
+
+func NewRelay(db *sql.DB, o Outbox, pub Publisher, opts ...Option) *Relay {
+	r := &Relay{
+		db:       db,
+		outbox:   o,
+		pub:      pub,
+		batch:    100,
+		interval: time.Second,
+		backoff:  exponentialBackoff,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run polls the outbox until the context is done.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		n, err := r.Poll(ctx)
+		if err != nil && r.onError != nil && ctx.Err() == nil {
+			r.onError(err)
+		}
+
+		if n == r.batch && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// Poll publishes available events once and returns number of processed events.
+// A poll takes at most one event of a key, failed events are postponed by the backoff.
+func (r *Relay) Poll(ctx context.Context) (n int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	events, err := r.take(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	deleteStmt, err := r.outbox.query(`DELETE FROM %s WHERE "id" = ?`)
+	if err != nil {
+		return 0, err
+	}
+
+	retryStmt, err := r.outbox.query(`UPDATE %s SET "attempts" = "attempts" + 1, "available_at" = ?, "last_error" = ? WHERE "id" = ?`)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range events {
+		if err := r.pub.Publish(ctx, e); err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+
+			next := time.Now().Add(r.backoff(e.Attempts + 1))
+
+			if _, err := tx.ExecContext(ctx, retryStmt, next.UnixMilli(), err.Error(), e.ID); err != nil {
+				return 0, fmt.Errorf("postpone event %d: %w", e.ID, err)
+			}
+
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, deleteStmt, e.ID); err != nil {
+			return 0, fmt.Errorf("delete event %d: %w", e.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return len(events), nil
+}
+
+func (r *Relay) take(ctx context.Context, tx *sql.Tx) (events []Event, err error) {
+	maxAttempts := r.maxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = int(^uint32(0) >> 1)
+	}
+
+	// events of a key wait for the earlier ones, which may be postponed, dead or taken by another relay
+	stmt, err := r.outbox.query(`SELECT "id", "topic", "key", "payload", "created_at", "attempts" FROM %[1]s AS "e"
+WHERE "available_at" <= ? AND "attempts" < ?
+AND ("key" = '' OR NOT EXISTS (SELECT 1 FROM %[1]s AS "p" WHERE "p"."key" = "e"."key" AND "p"."id" < "e"."id"))
+ORDER BY "id" LIMIT ?` + r.outbox.dialect.lock)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, stmt, time.Now().UnixMilli(), maxAttempts, r.batch)
+	if err != nil {
+		return nil, fmt.Errorf("select events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			e         Event
+			createdAt int64
+		)
+
+		if err := rows.Scan(&e.ID, &e.Topic, &e.Key, &e.Payload, &createdAt, &e.Attempts); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+
+		e.CreatedAt = time.UnixMilli(createdAt)
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select events: %w", err)
+	}
+
+	return events, rows.Close()
+}
+
+func exponentialBackoff(attempts int) time.Duration {
+	if attempts > 12 {
+		return time.Hour
+	}
+	d := time.Second << attempts
+	if d > time.Hour {
+		return time.Hour
+	}
+	return d
+}
@@ -0,0 +1,32 @@
+package norm
+
+import (
+	"context"
+)
+
+// PageRequest describes requested page.
+// Cursor is empty for the first page, otherwise it must be taken from the previous page.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+// Page is a part of the ordered values list.
+type Page[T any] struct {
+	Items   []T
+	HasNext bool
+	// Next is an opaque cursor of the next page, empty when HasNext is false.
+	Next string
+}
+
+type Pager[T, A any] interface {
+	Page(ctx context.Context, args A, req PageRequest) (page Page[T], err error)
+}
+
+// LastKey returns key of the last page item.
+func LastKey[T Keyer[K], K comparable](page Page[T]) (key K, ok bool) {
+	if len(page.Items) == 0 {
+		return key, false
+	}
+	return page.Items[len(page.Items)-1].Key(), true
+}
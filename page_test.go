@@ -0,0 +1,24 @@
+package norm
+
+import "testing"
+
+func TestLastKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    Page[kint]
+		wantKey int
+		wantOk  bool
+	}{
+		{"empty page", Page[kint]{}, 0, false},
+		{"one item", Page[kint]{Items: []kint{7}}, 7, true},
+		{"several items", Page[kint]{Items: []kint{1, 2, 3}, HasNext: true}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotKey, gotOk := LastKey[kint, int](tt.page)
+			if gotKey != tt.wantKey || gotOk != tt.wantOk {
+				t.Errorf("LastKey() = %v, %v, want %v, %v", gotKey, gotOk, tt.wantKey, tt.wantOk)
+			}
+		})
+	}
+}
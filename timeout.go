@@ -0,0 +1,30 @@
+package norm
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeoutError reports operation exceeded its deadline or the server statement timeout.
+// It matches ErrTimeout and unwraps to the cause, e.g. context.DeadlineExceeded.
+type TimeoutError struct {
+	Op Op
+	// Timeout is the default timeout of the operation, zero when the deadline was set by the caller.
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf("%s timed out after %s: %v", e.Op, e.Timeout, e.Err)
+	}
+	return fmt.Sprintf("%s timed out: %v", e.Op, e.Err)
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,41 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeoutError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *TimeoutError
+		want string
+	}{
+		{
+			name: "default timeout",
+			err:  &TimeoutError{Op: OpRead, Timeout: time.Second, Err: context.DeadlineExceeded},
+			want: "read timed out after 1s: context deadline exceeded",
+		},
+		{
+			name: "caller deadline",
+			err:  &TimeoutError{Op: OpCreate, Err: context.DeadlineExceeded},
+			want: "create timed out: context deadline exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(tt.err, ErrTimeout) {
+				t.Error("error doesn't match ErrTimeout")
+			}
+			if !errors.Is(tt.err, context.DeadlineExceeded) {
+				t.Error("error doesn't match context.DeadlineExceeded")
+			}
+		})
+	}
+}
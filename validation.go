@@ -0,0 +1,200 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Validator is implemented by models and args validated before create and update by Validated.
+type Validator interface {
+	Validate(ctx context.Context) error
+}
+
+// FieldError is a message about the invalid field named by its db tag.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists invalid fields, API layers can map it to 422 responses by errors.As.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("validation failed")
+	for i, f := range e.Fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		if f.Field != "" {
+			b.WriteString(f.Field)
+			b.WriteString(": ")
+		}
+		b.WriteString(f.Message)
+	}
+	return b.String()
+}
+
+// Fields collects field errors of T, fields are named by their db tags.
+//
+//	func (u *User) Validate(ctx context.Context) error {
+//		var f norm.Fields[User]
+//		f.Check(u.Name != "", "Name", "must not be empty")
+//		f.Check(u.Age >= 0, "Age", "must not be negative")
+//		return f.Err()
+//	}
+type Fields[T any] struct {
+	errs []FieldError
+}
+
+// Add records message of the field of T, the name of a missing field is kept as is.
+func (f *Fields[T]) Add(field, message string) {
+	f.errs = append(f.errs, FieldError{Field: fieldName[T](field), Message: message})
+}
+
+// Check records message of the field when ok is false.
+func (f *Fields[T]) Check(ok bool, field, message string) {
+	if !ok {
+		f.Add(field, message)
+	}
+}
+
+// Err returns *ValidationError of the recorded fields or nil.
+func (f *Fields[T]) Err() error {
+	if len(f.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: f.errs}
+}
+
+// Validated validates args and values before create and update:
+// Validator of args, Validator of the value, then the validate function.
+// Validation errors of all of them are merged into a single *ValidationError,
+// any other error is returned immediately.
+type Validated[M, A any] struct {
+	Object[M, A]
+	validate func(ctx context.Context, args A, value M) error
+}
+
+// NewValidated makes validated object, validate may be nil to check only Validator implementations.
+func NewValidated[M, A any](o Object[M, A], validate func(ctx context.Context, args A, value M) error) Validated[M, A] {
+	return Validated[M, A]{Object: o, validate: validate}
+}
+
+func (v Validated[M, A]) Create(ctx context.Context, args A, value M) error {
+	if err := v.check(ctx, args, value); err != nil {
+		return err
+	}
+	return v.Object.Create(ctx, args, value)
+}
+
+func (v Validated[M, A]) Update(ctx context.Context, args A, value M) error {
+	if err := v.check(ctx, args, value); err != nil {
+		return err
+	}
+	return v.Object.Update(ctx, args, value)
+}
+
+func (v Validated[M, A]) check(ctx context.Context, args A, value M) error {
+	var checks []func() error
+
+	for _, p := range []any{&args, &value} {
+		if h, ok := hookOf[Validator](reflect.ValueOf(p)); ok {
+			checks = append(checks, func() error { return h.Validate(ctx) })
+		}
+	}
+
+	if v.validate != nil {
+		checks = append(checks, func() error { return v.validate(ctx, args, value) })
+	}
+
+	var fields []FieldError
+
+	for _, check := range checks {
+		err := check()
+		if err == nil {
+			continue
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			return err
+		}
+
+		fields = append(fields, verr.Fields...)
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+
+	return nil
+}
+
+var fieldNames sync.Map // reflect.Type -> map[string]string
+
+// fieldName returns db tag of the field of T.
+func fieldName[T any](field string) string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	names, ok := fieldNames.Load(t)
+	if !ok {
+		names, _ = fieldNames.LoadOrStore(t, fieldNamesOf(t, map[string]string{}))
+	}
+
+	if name, ok := names.(map[string]string)[field]; ok {
+		return name
+	}
+	return field
+}
+
+func fieldNamesOf(t reflect.Type, names map[string]string) map[string]string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+
+	// fields of embedded structs are shadowed by the outer ones
+	var embedded []reflect.Type
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name, _, _ := strings.Cut(f.Tag.Get("db"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			embedded = append(embedded, f.Type)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		if _, ok := names[f.Name]; !ok {
+			names[f.Name] = name
+		}
+	}
+
+	for _, e := range embedded {
+		fieldNamesOf(e, names)
+	}
+
+	return names
+}
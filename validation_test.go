@@ -0,0 +1,159 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type validArgs struct {
+	ID string
+}
+
+func (a validArgs) Validate(ctx context.Context) error {
+	var f Fields[validArgs]
+	f.Check(a.ID != "", "ID", "must not be empty")
+	return f.Err()
+}
+
+type validBase struct {
+	Email string `db:"email"`
+}
+
+type validModel struct {
+	validBase
+	Name string `db:"user_name"`
+	Age  int    `db:"age,omitempty"`
+}
+
+func (m *validModel) Validate(ctx context.Context) error {
+	var f Fields[validModel]
+	f.Check(m.Name != "", "Name", "must not be empty")
+	f.Check(m.Email != "", "Email", "must not be empty")
+	return f.Err()
+}
+
+func TestFields(t *testing.T) {
+	var f Fields[validModel]
+
+	if err := f.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+
+	f.Add("Name", "a")
+	f.Add("Age", "b")
+	f.Add("Email", "c")
+	f.Add("Missing", "d")
+
+	want := &ValidationError{Fields: []FieldError{
+		{Field: "user_name", Message: "a"},
+		{Field: "age", Message: "b"},
+		{Field: "email", Message: "c"},
+		{Field: "Missing", Message: "d"},
+	}}
+
+	if err := f.Err(); !reflect.DeepEqual(err, want) {
+		t.Errorf("Err() = %v, want %v", err, want)
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{Fields: []FieldError{{Field: "a", Message: "x"}, {Message: "y"}}}
+	if got, want := err.Error(), "validation failed: a: x; y"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestValidated(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		args     validArgs
+		value    validModel
+		validate func(ctx context.Context, args validArgs, value validModel) error
+		want     []FieldError
+		wantErr  error
+	}{
+		{
+			name:  "valid",
+			args:  validArgs{ID: "1"},
+			value: validModel{Name: "a", validBase: validBase{Email: "e"}},
+		},
+		{
+			name:  "merged",
+			args:  validArgs{},
+			value: validModel{Name: "a", validBase: validBase{Email: "e"}, Age: -1},
+			validate: func(ctx context.Context, args validArgs, value validModel) error {
+				var f Fields[validModel]
+				f.Check(value.Age >= 0, "Age", "must not be negative")
+				return f.Err()
+			},
+			want: []FieldError{
+				{Field: "ID", Message: "must not be empty"},
+				{Field: "age", Message: "must not be negative"},
+			},
+		},
+		{
+			name:  "other error",
+			args:  validArgs{ID: "1"},
+			value: validModel{Name: "a", validBase: validBase{Email: "e"}},
+			validate: func(ctx context.Context, args validArgs, value validModel) error {
+				return errFailed
+			},
+			wantErr: errFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &validObject{}
+			v := NewValidated[validModel, validArgs](obj, tt.validate)
+
+			err := v.Create(context.Background(), tt.args, tt.value)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.want != nil:
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("Create() error = %v, want ValidationError", err)
+				}
+				if !reflect.DeepEqual(verr.Fields, tt.want) {
+					t.Errorf("Fields = %v, want %v", verr.Fields, tt.want)
+				}
+			case err != nil:
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			if wrote := err == nil; obj.created != wrote {
+				t.Errorf("created = %v, want %v", obj.created, wrote)
+			}
+		})
+	}
+}
+
+type validObject struct {
+	created bool
+}
+
+func (o *validObject) Create(ctx context.Context, args validArgs, value validModel) error {
+	o.created = true
+	return nil
+}
+
+func (o *validObject) Read(ctx context.Context, args validArgs) (validModel, error) {
+	return validModel{}, ErrNotFound
+}
+
+func (o *validObject) Update(ctx context.Context, args validArgs, value validModel) error {
+	return nil
+}
+
+func (o *validObject) Delete(ctx context.Context, args validArgs) error {
+	return nil
+}